@@ -120,6 +120,14 @@ import (
 
 const SunRadius float64 = 0.26667
 
+const EarthMeanRadius float64 = 6371000.0
+
+// coefficient of terrestrial refraction used for the refracted horizon dip
+const TerrestrialRefraction float64 = 0.13
+
+// ErrNoCrossing is returned if the sun does not cross the requested elevation on the given day
+var ErrNoCrossing = errors.New("sun does not cross the elevation on this day")
+
 const LCount int64 = 6
 const BCount int64 = 2
 const RCount int64 = 5
@@ -561,12 +569,27 @@ type Spa interface {
 	// Observer elevation [meters] valid range: -6500000 or higher meters
 	SetElevation(float64)
 	GetElevation() float64
-	// Annual average local pressure [millibars] valid range:    0 to 5000 millibars
+	// Observer altitude [kilometers], the elevation of high-altitude and orbital observers in kilometers
+	SetAltitudeKm(float64)
+	GetAltitudeKm() float64
+	// Annual average local pressure [millibars] valid range:    0 to 5000 millibars,
+	// math.NaN() to derive it from the elevation with the standard atmosphere
 	SetPressure(float64)
 	GetPressure() float64
-	// Annual average local temperature [degrees Celsius] valid range: -273 to 6000 degrees Celsius
+	// Pressure input not given and derived from the elevation with the standard atmosphere,
+	// also when a meteorology series replaces it in the calculation
+	GetPressureDefaulted() bool
+	// Annual average local temperature [degrees Celsius] valid range: -273 to 6000 degrees Celsius,
+	// math.NaN() to derive it from the elevation with the standard atmosphere
 	SetTemperature(float64)
 	GetTemperature() float64
+	// Temperature input not given and derived from the elevation with the standard atmosphere,
+	// also when a meteorology series replaces it in the calculation
+	GetTemperatureDefaulted() bool
+	// Pressure of the calculated instant [millibars], from the meteorology series, the input or the standard atmosphere
+	GetLocalPressure() float64
+	// Temperature of the calculated instant [degrees Celsius], from the meteorology series, the input or the standard atmosphere
+	GetLocalTemperature() float64
 	// Surface slope (measured from the horizontal plane) valid range: -360 to 360 degrees
 	SetSlope(float64)
 	GetSlope() float64
@@ -579,6 +602,55 @@ type Spa interface {
 	// Switch to choose functions for desired output (from enumeration)
 	SetSPAFunction(SPAFunctions)
 	GetSPAFunction() SPAFunctions
+	// Observer height above the surrounding terrain or sea, used for the horizon dip [meters] valid range: 0 or higher meters
+	SetObserverHeight(float64)
+	GetObserverHeight() float64
+	// Switch to choose the horizon dip correction for rise, set and crossing times (from enumeration)
+	SetHorizonDip(HorizonDips)
+	GetHorizonDip() HorizonDips
+	// Switch to choose the point of the solar disk which marks sunrise and sunset (from enumeration)
+	SetRiseSetDefinition(RiseSetDefinitions)
+	GetRiseSetDefinition() RiseSetDefinitions
+	// Fraction of the sun radius above the disk center marking sunrise and sunset for RiseSetCustomDisk
+	// (1 upper limb, 0 center, -1 lower limb) valid range: -1 to 1
+	SetDiskFraction(float64)
+	GetDiskFraction() float64
+	// Derive the atmospheric refraction at sunrise and sunset from pressure and temperature instead of atmosRefract
+	SetAtmosRefractFromWeather(bool)
+	GetAtmosRefractFromWeather() bool
+	// Atmospheric refraction model, nil for the SPA formula
+	SetRefractionModel(RefractionModel)
+	GetRefractionModel() RefractionModel
+	// Local horizon profile for valleys and obstructions, nil for a flat horizon
+	SetHorizonProfile(*HorizonProfile)
+	GetHorizonProfile() *HorizonProfile
+	// Reference ellipsoid of the geodetic latitude and elevation (from enumeration)
+	SetEllipsoid(Ellipsoids)
+	GetEllipsoid() Ellipsoids
+	// Observer position as earth-centred earth-fixed coordinates [meters], converted to the geodetic latitude,
+	// longitude and elevation of the selected ellipsoid, also when the ellipsoid is selected later
+	SetObserverECEF(x float64, y float64, z float64)
+	// Switch to choose the observer environment (from enumeration), ObserverSpace for observers above the atmosphere.
+	// In space the rise, transit and set times are the sun crossing the earth limb for an observer fixed above
+	// the earth surface (aircraft, balloon), they do not apply to orbiting observers
+	SetObserverMode(ObserverModes)
+	GetObserverMode() ObserverModes
+	// Switch to choose the nutation series (from enumeration)
+	SetNutationModel(NutationModels)
+	GetNutationModel() NutationModels
+	// Heliocentric earth position (VSOP87, JPLEphemeris), nil for the truncated VSOP87 series of the SPA
+	SetEarthEphemeris(EarthEphemeris)
+	GetEarthEphemeris() EarthEphemeris
+	// IERS polar motion coordinates xp and yp [arc seconds] valid range: -1 to 1 arc seconds
+	SetPolarMotion(xp float64, yp float64)
+	GetPolarMotion() (xp float64, yp float64)
+	// Apply the diurnal aberration of the observer moving with the earth rotation
+	SetDiurnalAberration(bool)
+	GetDiurnalAberration() bool
+	// Time-varying pressure and temperature, nil for the fixed values. The interpolated values replace the
+	// pressure and temperature, the refraction at sunrise and sunset follows the weather at the rise and set times.
+	SetMeteorology(*MeteoSeries)
+	GetMeteorology() *MeteoSeries
 	//-----------------Intermediate OUTPUT VALUES--------------------
 	//Julian day
 	GetJd() float64
@@ -634,10 +706,18 @@ type Spa interface {
 	GetH() float64
 	//sun equatorial horizontal parallax [degrees]
 	GetXi() float64
+	//observer geocentric latitude [degrees]
+	GetGeocentricLatitude() float64
+	//observer distance from the earth center [meters]
+	GetGeocentricRadius() float64
 	//sun right ascension parallax [degrees]
 	GetDelAlpha() float64
 	//topocentric sun declination [degrees]
 	GetDeltaPrime() float64
+	//topocentric elevation and azimuth angle change by the polar motion [degrees]
+	GetPolarMotionEffect() (elevation float64, azimuth float64)
+	//topocentric elevation and azimuth angle change by the diurnal aberration [degrees]
+	GetDiurnalAberrationEffect() (elevation float64, azimuth float64)
 	//topocentric sun right ascension [degrees]
 	GetAlphaPrime() float64
 	//topocentric local hour angle [degrees]
@@ -656,21 +736,86 @@ type Spa interface {
 	GetSsha() float64
 	//sun transit altitude [degrees]
 	GetSta() float64
+	//horizon dip [degrees]
+	GetDip() float64
+	//depression of the earth limb (ellipsoid) below the horizontal [degrees]
+	GetLimbDepression() float64
+	//sun altitude at sunrise and sunset [degrees]
+	GetH0Prime() float64
 	//---------------------Final OUTPUT VALUES------------------------
-	//topocentric zenith angle [degrees]
+	//topocentric zenith angle (corrected) [degrees]
 	GetZenith() float64
+	//topocentric zenith angle (uncorrected) [degrees]
+	GetZenith0() float64
 	//topocentric azimuth angle (westward from south) [for astronomers]
 	GetAzimuthAstro() float64
 	//topocentric azimuth angle (eastward from north) [for navigators and solar radiation]
 	GetAzimuth() float64
 	//surface incidence angle [degrees]
 	GetIncidence() float64
+	//apparent (refraction corrected) and geometric topocentric sun position
+	GetSolarPosition() SolarPosition
+	//sun direction unit vectors (topocentric ENU, earth-fixed ECEF, inertial J2000) and sun distance
+	GetSunVectors() SunVectors
+	//geocentric sun right ascension and declination [degrees] in the equatorial frame, with the annual aberration
+	GetSunEquatorial(frame EquatorialFrames) (alpha float64, delta float64)
+	//apparent geocentric and topocentric position of a planet from its VSOP87 version D series, after Calculate
+	GetPlanetPosition(planet *VSOP87) (PlanetPosition, error)
+	//right ascension and declination [degrees] converted between equatorial frames at the date of the instance
+	ConvertEquatorial(alpha float64, delta float64, from EquatorialFrames, to EquatorialFrames) (float64, float64)
+	//local horizon elevation at the topocentric azimuth angle [degrees]
+	GetHorizonElevation() float64
+	//sun center above the local horizon (horizon profile or flat horizon)
+	GetSunAboveHorizon() bool
+	//fraction of the solar disk area above the local horizon, from 0 to 1
+	GetDiskVisibleFraction() float64
+	//solar disk completely hidden behind the earth limb
+	GetSunOcculted() bool
+	//apparent angular semi-diameter of the sun [degrees]
+	GetSunSemiDiameter() float64
+	//position angle of the northern extremity of the solar rotation axis, P, eastward from the north point of the disk [degrees]
+	GetSolarP() float64
+	//heliographic latitude of the center of the solar disk, B0 [degrees]
+	GetSolarB0() float64
+	//heliographic (Carrington) longitude of the center of the solar disk, L0 [degrees]
+	GetSolarL0() float64
+	//Carrington rotation number with the fraction of the rotation
+	GetCarringtonRotation() float64
 	//local sun transit time (or solar noon) [fractional hour]
 	GetSuntransit() float64
+	//local sun transit time (or solar noon)
+	GetSuntransitTime() time.Time
 	//local sunrise time (+/- 30 seconds) [fractional hour]
 	GetSunrise() time.Time
 	//local sunset time (+/- 30 seconds) [fractional hour]
 	GetSunset() time.Time
+	//topocentric azimuth angle at sunrise (eastward from north) [degrees]
+	GetSunriseAzimuth() float64
+	//topocentric azimuth angle at sunset (eastward from north) [degrees]
+	GetSunsetAzimuth() float64
+	//daylight duration from sunrise to sunset [fractional hour]
+	GetDayLength() float64
+	//change of the daylight duration compared with the previous day [minutes]
+	GetDayLengthChange() float64
+	//local rise and set time of the sun center at a custom elevation [degrees], corrected by the horizon dip
+	GetElevationCrossing(elevation float64) (rise time.Time, set time.Time, err error)
+	//local rise and set time of the sun center at a custom elevation [degrees] above the true horizontal, without the horizon dip
+	GetAltitudeCrossing(elevation float64) (rise time.Time, set time.Time, err error)
+	//local dawn and dusk time of the selected twilight, corrected by the horizon dip
+	GetTwilight(twilight Twilights) (dawn time.Time, dusk time.Time, err error)
+	//local periods of the calculated day with the sun above the local horizon, calculated once per Calculate
+	GetSunlightIntervals() ([]SunlightInterval, error)
+	//local time of the first sunrise above the local horizon
+	GetEffectiveSunrise() (time.Time, error)
+	//local time of the last sunset below the local horizon
+	GetEffectiveSunset() (time.Time, error)
+	//instants from start to end when the sun crosses the elevation (corrected) [degrees] within the tolerance [degrees]
+	//of the azimuth (eastward from north) [degrees], use math.NaN() as elevation for the local horizon at the azimuth
+	FindAlignments(azimuth float64, elevation float64, tolerance float64, start time.Time, end time.Time) ([]Alignment, error)
+	//true (geometric) elevation [degrees] of an apparent (observed) elevation [degrees] with the selected refraction model
+	GetTrueElevation(e float64) float64
+	//calculated copies of the instance for each date, with the meteorology interpolated at each date
+	CalculateSeries(dates []time.Time) ([]Spa, error)
 }
 
 // NewSpa creates new SPA instance
@@ -681,8 +826,8 @@ func NewSpa(dt time.Time, latitude float64, longitude float64, elevation float64
 	s.latitude = latitude
 	s.longitude = longitude
 	s.elevation = elevation
-	s.pressure = pressure
-	s.temperature = temperature
+	s.SetPressure(pressure)
+	s.SetTemperature(temperature)
 	s.deltaT = deltaT
 	s.deltaUt1 = deltaUt1
 	s.slope = slope
@@ -734,6 +879,9 @@ type spa struct {
 	temperature float64 // Annual average local temperature [degrees Celsius]
 	// valid range: -273 to 6000 degrees Celsius, error code; 13
 
+	pressureDefaulted    bool // Derive the pressure from the elevation with the standard atmosphere
+	temperatureDefaulted bool // Derive the temperature from the elevation with the standard atmosphere
+
 	slope float64 // Surface slope (measured from the horizontal plane)
 	// valid range: -360 to 360 degrees, error code: 14
 
@@ -746,8 +894,46 @@ type spa struct {
 
 	function SPAFunctions // Switch to choose functions for desired output (from enumeration)
 
+	observerHeight float64 // Observer height above the surrounding terrain or sea [meters]
+	// valid range: 0 or higher meters, error code: 18
+
+	horizonDip HorizonDips // Switch to choose the horizon dip correction (from enumeration)
+
+	riseSetDefinition RiseSetDefinitions // Switch to choose the point of the solar disk which marks sunrise and sunset (from enumeration)
+
+	diskFraction float64 // Fraction of the sun radius marking sunrise and sunset for RiseSetCustomDisk
+	// valid range: -1 to 1, error code: 19
+
+	atmosRefractFromWeather bool // Derive the atmospheric refraction at sunrise and sunset from pressure and temperature
+
+	refractionModel RefractionModel // Atmospheric refraction model, nil for the SPA formula
+
+	horizonProfile *HorizonProfile // Local horizon profile, nil for a flat horizon
+
+	ellipsoid Ellipsoids // Reference ellipsoid of the geodetic latitude and elevation (from enumeration)
+
+	ecef    [3]float64 // Observer earth-centred earth-fixed coordinates [meters]
+	ecefSet bool       // Observer position given as earth-centred earth-fixed coordinates, converted with the ellipsoid
+
+	observerMode ObserverModes // Switch to choose the observer environment (from enumeration)
+
+	nutationModel NutationModels // Switch to choose the nutation series (from enumeration)
+
+	earthEphemeris EarthEphemeris // Heliocentric earth position, nil for the truncated VSOP87 series of the SPA
+
+	xp float64 // IERS polar motion coordinate x [arc seconds]
+	yp float64 // IERS polar motion coordinate y [arc seconds]
+	// valid range: -1 to 1 arc seconds, error code: 20
+
+	diurnalAberration bool // Apply the diurnal aberration of the observer
+
+	meteorology *MeteoSeries // Time-varying pressure and temperature, nil for the fixed values
+
 	//-----------------Intermediate OUTPUT VALUES--------------------
 
+	localPressure    float64 //pressure of the calculated instant, from the meteorology series or the input [millibars]
+	localTemperature float64 //temperature of the calculated instant, from the meteorology series or the input [degrees Celsius]
+
 	jd float64 //Julian day
 	jc float64 //Julian century
 
@@ -783,8 +969,16 @@ type spa struct {
 
 	h          float64 //observer hour angle [degrees]
 	xi         float64 //sun equatorial horizontal parallax [degrees]
+	phiPrime   float64 //observer geocentric latitude [degrees]
+	rho        float64 //observer distance from the earth center [meters]
 	delAlpha   float64 //sun right ascension parallax [degrees]
 	deltaPrime float64 //topocentric sun declination [degrees]
+
+	polarMotionE             float64 //topocentric elevation angle change by the polar motion [degrees]
+	polarMotionAzimuth       float64 //topocentric azimuth angle change by the polar motion [degrees]
+	diurnalAberrationE       float64 //topocentric elevation angle change by the diurnal aberration [degrees]
+	diurnalAberrationAzimuth float64 //topocentric azimuth angle change by the diurnal aberration [degrees]
+
 	alphaPrime float64 //topocentric sun right ascension [degrees]
 	hPrime     float64 //topocentric local hour angle [degrees]
 
@@ -798,17 +992,46 @@ type spa struct {
 	sta  float64 //sun transit altitude [degrees]
 	mRts []float64
 
+	dip            float64 //horizon dip [degrees]
+	limbDepression float64 //depression of the earth limb below the horizontal [degrees]
+	h0Prime        float64 //sun altitude at sunrise and sunset [degrees]
+
+	rtsAlpha []float64 //geocentric sun right ascension at 0 TT of the previous, current and next day [degrees]
+	rtsDelta []float64 //geocentric sun declination at 0 TT of the previous, current and next day [degrees]
+	rtsNu    float64   //Greenwich sidereal time at 0 UT [degrees]
+
 	//---------------------Final OUTPUT VALUES------------------------
 
-	zenith       float64 //topocentric zenith angle [degrees]
+	zenith       float64 //topocentric zenith angle (corrected) [degrees]
+	zenith0      float64 //topocentric zenith angle (uncorrected) [degrees]
 	azimuthAstro float64 //topocentric azimuth angle (westward from south) [for astronomers]
 	azimuth      float64 //topocentric azimuth angle (eastward from north) [for navigators and solar radiation]
 	incidence    float64 //surface incidence angle [degrees]
 
+	horizonEl       float64 //local horizon elevation at the topocentric azimuth angle [degrees]
+	sunAboveHorizon bool    //sun center above the local horizon
+	sunOcculted     bool    //solar disk completely hidden behind the earth limb
+
+	diskVisibleFraction float64 //fraction of the solar disk area above the local horizon
+
+	sunlightIntervals  []SunlightInterval //periods of the calculated day with the sun above the local horizon, calculated on request
+	sunlightCalculated bool               //sunlight intervals of the calculated day are available
+
+	semiDiameter  float64 //apparent angular semi-diameter of the sun [degrees]
+	positionAngle float64 //position angle of the northern extremity of the solar rotation axis, P [degrees]
+	b0            float64 //heliographic latitude of the center of the solar disk, B0 [degrees]
+	l0            float64 //heliographic (Carrington) longitude of the center of the solar disk, L0 [degrees]
+	carrington    float64 //Carrington rotation number, the fraction counts from L0 = 360 degrees
+
 	suntransit float64 //local sun transit time (or solar noon) [fractional hour]
 	sunrise    float64 //local sunrise time (+/- 30 seconds) [fractional hour]
 	sunset     float64 //local sunset time (+/- 30 seconds) [fractional hour]
 
+	sunriseAzimuth  float64 //topocentric azimuth angle at sunrise (eastward from north) [degrees]
+	sunsetAzimuth   float64 //topocentric azimuth angle at sunset (eastward from north) [degrees]
+	dayLength       float64 //daylight duration from sunrise to sunset [fractional hour]
+	dayLengthChange float64 //change of the daylight duration compared with the previous day [minutes]
+
 }
 
 func (s *spa) GetJd() float64 {
@@ -919,6 +1142,14 @@ func (s *spa) GetXi() float64 {
 	return s.xi
 }
 
+func (s *spa) GetGeocentricLatitude() float64 {
+	return s.phiPrime
+}
+
+func (s *spa) GetGeocentricRadius() float64 {
+	return s.rho
+}
+
 func (s *spa) GetDelAlpha() float64 {
 	return s.delAlpha
 }
@@ -927,6 +1158,14 @@ func (s *spa) GetDeltaPrime() float64 {
 	return s.deltaPrime
 }
 
+func (s *spa) GetPolarMotionEffect() (elevation float64, azimuth float64) {
+	return s.polarMotionE, s.polarMotionAzimuth
+}
+
+func (s *spa) GetDiurnalAberrationEffect() (elevation float64, azimuth float64) {
+	return s.diurnalAberrationE, s.diurnalAberrationAzimuth
+}
+
 func (s *spa) GetAlphaPrime() float64 {
 	return s.alphaPrime
 }
@@ -963,10 +1202,46 @@ func (s *spa) GetSta() float64 {
 	return s.sta
 }
 
+func (s *spa) GetDip() float64 {
+	return s.dip
+}
+
+func (s *spa) GetLimbDepression() float64 {
+	return s.limbDepression
+}
+
+func (s *spa) GetH0Prime() float64 {
+	return s.h0Prime
+}
+
 func (s *spa) GetZenith() float64 {
 	return s.zenith
 }
 
+func (s *spa) GetZenith0() float64 {
+	return s.zenith0
+}
+
+func (s *spa) GetSolarPosition() SolarPosition {
+	return s.solarPosition()
+}
+
+func (s *spa) GetSunVectors() SunVectors {
+	return s.sunVectors()
+}
+
+func (s *spa) GetSunEquatorial(frame EquatorialFrames) (alpha float64, delta float64) {
+	return s.convertEquatorial(s.alpha, s.delta, FrameTrueOfDate, frame)
+}
+
+func (s *spa) GetPlanetPosition(planet *VSOP87) (PlanetPosition, error) {
+	return s.planetPosition(planet)
+}
+
+func (s *spa) ConvertEquatorial(alpha float64, delta float64, from EquatorialFrames, to EquatorialFrames) (float64, float64) {
+	return s.convertEquatorial(alpha, delta, from, to)
+}
+
 func (s *spa) GetAzimuthAstro() float64 {
 	return s.azimuthAstro
 }
@@ -979,24 +1254,114 @@ func (s *spa) GetIncidence() float64 {
 	return s.incidence
 }
 
+func (s *spa) GetHorizonElevation() float64 {
+	return s.horizonEl
+}
+
+func (s *spa) GetSunAboveHorizon() bool {
+	return s.sunAboveHorizon
+}
+
+func (s *spa) GetDiskVisibleFraction() float64 {
+	return s.diskVisibleFraction
+}
+
+func (s *spa) GetSunOcculted() bool {
+	return s.sunOcculted
+}
+
+func (s *spa) GetSunSemiDiameter() float64 {
+	return s.semiDiameter
+}
+
+func (s *spa) GetSolarP() float64 {
+	return s.positionAngle
+}
+
+func (s *spa) GetSolarB0() float64 {
+	return s.b0
+}
+
+func (s *spa) GetSolarL0() float64 {
+	return s.l0
+}
+
+func (s *spa) GetCarringtonRotation() float64 {
+	return s.carrington
+}
+
 func (s *spa) GetSuntransit() float64 {
 	return s.suntransit
 }
 
+func (s *spa) GetSuntransitTime() time.Time {
+	return s.localHourToDate(s.suntransit)
+}
+
 func (s *spa) GetSunrise() time.Time {
-	h, m, sec := s.calculateHourMinSec(s.sunrise)
-	dt := time.Date(s.year, time.Month(s.month), s.day, 0, 0, 0, 0, time.FixedZone("ManualTimeZone", int(s.timezone*3600)))
-	return dt.Add(time.Hour*time.Duration(h) +
-		time.Minute*time.Duration(m) +
-		time.Second*time.Duration(sec))
+	return s.localHourToDate(s.sunrise)
 }
 
 func (s *spa) GetSunset() time.Time {
-	h, m, sec := s.calculateHourMinSec(s.sunset)
-	dt := time.Date(s.year, time.Month(s.month), s.day, 0, 0, 0, 0, time.FixedZone("ManualTimeZone", int(s.timezone*3600)))
-	return dt.Add(time.Hour*time.Duration(h) +
-		time.Minute*time.Duration(m) +
-		time.Second*time.Duration(sec))
+	return s.localHourToDate(s.sunset)
+}
+
+func (s *spa) GetSunriseAzimuth() float64 {
+	return s.sunriseAzimuth
+}
+
+func (s *spa) GetSunsetAzimuth() float64 {
+	return s.sunsetAzimuth
+}
+
+func (s *spa) GetDayLength() float64 {
+	return s.dayLength
+}
+
+func (s *spa) GetDayLengthChange() float64 {
+	return s.dayLengthChange
+}
+
+func (s *spa) GetElevationCrossing(elevation float64) (rise time.Time, set time.Time, err error) {
+	if s.rtsAlpha == nil {
+		return rise, set, errors.New("rise/transit/set values not calculated")
+	}
+	if math.Abs(elevation) > 90 {
+		return rise, set, errors.New("invalid elevation")
+	}
+	return s.elevationCrossing(elevation - s.dip)
+}
+
+func (s *spa) GetAltitudeCrossing(elevation float64) (rise time.Time, set time.Time, err error) {
+	if s.rtsAlpha == nil {
+		return rise, set, errors.New("rise/transit/set values not calculated")
+	}
+	if math.Abs(elevation) > 90 {
+		return rise, set, errors.New("invalid elevation")
+	}
+	return s.elevationCrossing(elevation)
+}
+
+// elevationCrossing calculates the local times when the sun center crosses the elevation [degrees] above the
+// true horizontal
+func (s *spa) elevationCrossing(elevation float64) (rise time.Time, set time.Time, err error) {
+	rts, ok := s.sunRiseTransitSet(elevation)
+	if !ok {
+		return rise, set, ErrNoCrossing
+	}
+	return s.localHourToDate(rts.rise), s.localHourToDate(rts.set), nil
+}
+
+func (s *spa) GetTwilight(twilight Twilights) (dawn time.Time, dusk time.Time, err error) {
+	switch twilight {
+	case CivilTwilight:
+		return s.GetElevationCrossing(-6)
+	case NauticalTwilight:
+		return s.GetElevationCrossing(-12)
+	case AstronomicalTwilight:
+		return s.GetElevationCrossing(-18)
+	}
+	return dawn, dusk, errors.New("invalid twilight")
 }
 
 func (s *spa) SetDate(dt time.Time) {
@@ -1011,9 +1376,93 @@ func (s *spa) SetDate(dt time.Time) {
 
 }
 
+// setLocalDate sets the date and time fields from the date in the observer time zone, keeping the time zone
+func (s *spa) setLocalDate(dt time.Time) {
+	dt = dt.In(time.FixedZone("ManualTimeZone", int(s.timezone*3600)))
+	s.year = dt.Year()
+	s.month = int(dt.Month())
+	s.day = dt.Day()
+	s.hour = dt.Hour()
+	s.minute = dt.Minute()
+	s.second = float64(dt.Second())
+}
+
 func (s *spa) GetDate() time.Time {
 	return time.Date(s.year, time.Month(s.month), s.day, s.hour, s.minute, int(s.second), 0, time.FixedZone("ManualTimeZone", int(s.timezone*3600)))
 }
+func (s *spa) GetSunlightIntervals() ([]SunlightInterval, error) {
+	intervals, err := s.sunlight()
+	return append([]SunlightInterval(nil), intervals...), err
+}
+
+func (s *spa) GetEffectiveSunrise() (time.Time, error) {
+	intervals, err := s.sunlight()
+	if err != nil {
+		return time.Time{}, err
+	}
+	dayStart := s.daySecondToDate(0)
+	for _, interval := range intervals {
+		if interval.Start.After(dayStart) {
+			return interval.Start, nil
+		}
+	}
+	return time.Time{}, ErrNoCrossing
+}
+
+func (s *spa) GetEffectiveSunset() (time.Time, error) {
+	intervals, err := s.sunlight()
+	if err != nil {
+		return time.Time{}, err
+	}
+	dayEnd := s.daySecondToDate(86400)
+	for i := len(intervals) - 1; i >= 0; i-- {
+		if intervals[i].End.Before(dayEnd) {
+			return intervals[i].End, nil
+		}
+	}
+	return time.Time{}, ErrNoCrossing
+}
+
+func (s *spa) FindAlignments(azimuth float64, elevation float64, tolerance float64, start time.Time, end time.Time) ([]Alignment, error) {
+	return s.calculateAlignments(azimuth, elevation, tolerance, start, end)
+}
+
+func (s *spa) CalculateSeries(dates []time.Time) ([]Spa, error) {
+	return s.calculateSeries(dates)
+}
+
+func (s *spa) GetTrueElevation(e float64) float64 {
+	if s.observerMode == ObserverSpace {
+		return e
+	}
+	if s.refractionModel == nil {
+		return TrueElevation(SpaRefraction{}, e, s.refractionConditions())
+	}
+	return TrueElevation(s.refractionModel, e, s.refractionConditions())
+}
+
+// applyStandardAtmosphere derives the pressure and temperature of the calculation which are not given from
+// the elevation, the inputs are left unchanged. An observer in space has no weather.
+func (s *spa) applyStandardAtmosphere() {
+	if s.observerMode == ObserverSpace || (!s.pressureDefaulted && !s.temperatureDefaulted) {
+		return
+	}
+	pressure, temperature := StandardAtmosphere(s.elevation)
+	if s.pressureDefaulted {
+		s.localPressure = pressure
+	}
+	if s.temperatureDefaulted {
+		s.localTemperature = temperature
+	}
+}
+
+func (s *spa) localHourToDate(decHours float64) time.Time {
+	h, m, sec := s.calculateHourMinSec(decHours)
+	dt := time.Date(s.year, time.Month(s.month), s.day, 0, 0, 0, 0, time.FixedZone("ManualTimeZone", int(s.timezone*3600)))
+	return dt.Add(time.Hour*time.Duration(h) +
+		time.Minute*time.Duration(m) +
+		time.Second*time.Duration(sec))
+}
 func (s *spa) calculateHourMinSec(decHours float64) (hours int, minutes int, seconds int) {
 	min := 60.0 * (decHours - float64(int(decHours)))
 	sec := 60.0 * (min - float64(int(min)))
@@ -1086,6 +1535,7 @@ func (s *spa) GetTimezone() float64 {
 
 func (s *spa) SetLongitude(lon float64) {
 	s.longitude = lon
+	s.ecefSet = false
 }
 
 func (s *spa) GetLongitude() float64 {
@@ -1094,6 +1544,7 @@ func (s *spa) GetLongitude() float64 {
 
 func (s *spa) SetLatitude(lat float64) {
 	s.latitude = lat
+	s.ecefSet = false
 }
 
 func (s *spa) GetLatitude() float64 {
@@ -1102,28 +1553,57 @@ func (s *spa) GetLatitude() float64 {
 
 func (s *spa) SetElevation(elevation float64) {
 	s.elevation = elevation
+	s.ecefSet = false
 }
 
 func (s *spa) GetElevation() float64 {
 	return s.elevation
 }
 
+func (s *spa) SetAltitudeKm(altitude float64) {
+	s.SetElevation(altitude * 1000.0)
+}
+
+func (s *spa) GetAltitudeKm() float64 {
+	return s.elevation / 1000.0
+}
+
 func (s *spa) SetPressure(pressure float64) {
+	s.pressureDefaulted = math.IsNaN(pressure)
 	s.pressure = pressure
+	s.applyMeteorology(s.GetDate())
 }
 
 func (s *spa) GetPressure() float64 {
 	return s.pressure
 }
 
+func (s *spa) GetPressureDefaulted() bool {
+	return s.pressureDefaulted
+}
+
 func (s *spa) SetTemperature(temp float64) {
+	s.temperatureDefaulted = math.IsNaN(temp)
 	s.temperature = temp
+	s.applyMeteorology(s.GetDate())
 }
 
 func (s *spa) GetTemperature() float64 {
 	return s.temperature
 }
 
+func (s *spa) GetTemperatureDefaulted() bool {
+	return s.temperatureDefaulted
+}
+
+func (s *spa) GetLocalPressure() float64 {
+	return s.localPressure
+}
+
+func (s *spa) GetLocalTemperature() float64 {
+	return s.localTemperature
+}
+
 func (s *spa) SetSlope(slope float64) {
 	s.slope = slope
 }
@@ -1156,6 +1636,129 @@ func (s *spa) GetSPAFunction() SPAFunctions {
 	return s.function
 }
 
+func (s *spa) SetObserverHeight(height float64) {
+	s.observerHeight = height
+}
+
+func (s *spa) GetObserverHeight() float64 {
+	return s.observerHeight
+}
+
+func (s *spa) SetHorizonDip(dip HorizonDips) {
+	s.horizonDip = dip
+}
+
+func (s *spa) GetHorizonDip() HorizonDips {
+	return s.horizonDip
+}
+
+func (s *spa) SetRiseSetDefinition(definition RiseSetDefinitions) {
+	s.riseSetDefinition = definition
+}
+
+func (s *spa) GetRiseSetDefinition() RiseSetDefinitions {
+	return s.riseSetDefinition
+}
+
+func (s *spa) SetDiskFraction(fraction float64) {
+	s.diskFraction = fraction
+}
+
+func (s *spa) GetDiskFraction() float64 {
+	return s.diskFraction
+}
+
+func (s *spa) SetAtmosRefractFromWeather(fromWeather bool) {
+	s.atmosRefractFromWeather = fromWeather
+}
+
+func (s *spa) GetAtmosRefractFromWeather() bool {
+	return s.atmosRefractFromWeather
+}
+
+func (s *spa) SetRefractionModel(model RefractionModel) {
+	s.refractionModel = model
+}
+
+func (s *spa) GetRefractionModel() RefractionModel {
+	return s.refractionModel
+}
+
+func (s *spa) SetHorizonProfile(profile *HorizonProfile) {
+	s.horizonProfile = profile
+}
+
+func (s *spa) GetHorizonProfile() *HorizonProfile {
+	return s.horizonProfile
+}
+
+func (s *spa) SetEllipsoid(ellipsoid Ellipsoids) {
+	s.ellipsoid = ellipsoid
+	if s.ecefSet {
+		s.latitude, s.longitude, s.elevation = ECEFToGeodetic(s.ellipsoid, s.ecef[0], s.ecef[1], s.ecef[2])
+	}
+}
+
+func (s *spa) GetEllipsoid() Ellipsoids {
+	return s.ellipsoid
+}
+
+func (s *spa) SetObserverECEF(x float64, y float64, z float64) {
+	s.ecef = [3]float64{x, y, z}
+	s.ecefSet = true
+	s.latitude, s.longitude, s.elevation = ECEFToGeodetic(s.ellipsoid, x, y, z)
+}
+
+func (s *spa) SetObserverMode(mode ObserverModes) {
+	s.observerMode = mode
+}
+
+func (s *spa) GetObserverMode() ObserverModes {
+	return s.observerMode
+}
+
+func (s *spa) SetNutationModel(model NutationModels) {
+	s.nutationModel = model
+}
+
+func (s *spa) GetNutationModel() NutationModels {
+	return s.nutationModel
+}
+
+func (s *spa) SetEarthEphemeris(ephemeris EarthEphemeris) {
+	s.earthEphemeris = ephemeris
+}
+
+func (s *spa) GetEarthEphemeris() EarthEphemeris {
+	return s.earthEphemeris
+}
+
+func (s *spa) SetPolarMotion(xp float64, yp float64) {
+	s.xp = xp
+	s.yp = yp
+}
+
+func (s *spa) GetPolarMotion() (xp float64, yp float64) {
+	return s.xp, s.yp
+}
+
+func (s *spa) SetDiurnalAberration(diurnalAberration bool) {
+	s.diurnalAberration = diurnalAberration
+}
+
+func (s *spa) GetDiurnalAberration() bool {
+	return s.diurnalAberration
+}
+
+func (s *spa) SetMeteorology(meteorology *MeteoSeries) {
+	s.meteorology = meteorology
+	s.applyMeteorology(s.GetDate())
+}
+
+func (s *spa) GetMeteorology() *MeteoSeries {
+	return s.meteorology
+}
+
 func (s *spa) init() {
 	// use  some dummy values for init
 	s.year = 2003
@@ -1170,12 +1773,20 @@ func (s *spa) init() {
 	s.longitude = -105.1786
 	s.latitude = 39.742476
 	s.elevation = 1830.14
-	s.pressure = 820
-	s.temperature = 11
+	s.pressure = math.NaN()
+	s.temperature = math.NaN()
+	s.pressureDefaulted = true
+	s.temperatureDefaulted = true
 	s.slope = 30
 	s.azmRotation = -10
 	s.atmosRefract = 0.5667
 	s.function = SpaAll
+	s.observerHeight = 0
+	s.horizonDip = DipNone
+	s.riseSetDefinition = RiseSetNrel
+	s.diskFraction = 1
+	s.atmosRefractFromWeather = false
+	s.ellipsoid = EllipsoidIAU1976
 }
 
 //Calculate SPA output values (in structure) based on input values passed in structure
@@ -1183,6 +1794,8 @@ func (s *spa) Calculate() error {
 
 	// renew the date
 	s.SetDate(s.GetDate())
+	s.sunlightIntervals, s.sunlightCalculated = nil, false
+	s.applyMeteorology(s.GetDate())
 
 	err := s.validate()
 	if err != nil {
@@ -1193,31 +1806,59 @@ func (s *spa) Calculate() error {
 		s.minute, s.second, s.deltaUt1, s.timezone)
 
 	s.calculateGeocentricSunRightAscensionAndDeclination()
+	if math.IsNaN(s.r) {
+		return errors.New("date out of the earth ephemeris range")
+	}
 
 	s.h = s.observerHourAngle(s.nu, s.longitude, s.alpha)
 	s.xi = s.sunEquatorialHorizontalParallax(s.r)
 
 	s.rightAscensionParallaxAndTopocentricDec(s.latitude, s.elevation, s.xi, s.h, s.delta)
+	s.phiPrime, s.rho = s.observerGeocentricLatitude()
 
 	s.alphaPrime = s.topocentricRightAscension(s.alpha, s.delAlpha)
 	s.hPrime = s.topocentricLocalHourAngle(s.h, s.delAlpha)
+	s.topocentricCorrections()
 
 	s.e0 = s.topocentricElevationAngle(s.latitude, s.deltaPrime, s.hPrime)
-	s.delE = s.atmosphericRefractionCorrection(s.pressure, s.temperature,
-		s.atmosRefract, s.e0)
+	s.delE = s.refraction(s.e0)
 	s.e = s.topocentricElevationAngleCorrected(s.e0, s.delE)
 
 	s.zenith = s.topocentricZenithAngle(s.e)
+	s.zenith0 = s.topocentricZenithAngle(s.e0)
 	s.azimuthAstro = s.topocentricAzimuthAngleAstro(s.hPrime, s.latitude,
 		s.deltaPrime)
 	s.azimuth = s.topocentricAzimuthAngle(s.azimuthAstro)
 
+	s.limbDepression = s.earthLimbDepression()
+	s.dip = s.horizonDipAngle(s.observerHeight, s.horizonDip)
+	if s.observerMode == ObserverSpace {
+		s.dip = s.limbDepression
+	}
+	s.sunOcculted = s.e0+s.sunSemiDiameter(s.r) < -s.limbDepression
+	s.horizonEl = s.horizonElevation(s.azimuth)
+	s.sunAboveHorizon = s.e > s.horizonEl
+	s.diskVisibleFraction = s.sunDiskVisibleFraction(s.e0, s.r, s.horizonEl)
+
+	s.semiDiameter = s.sunSemiDiameter(s.r)
+	s.solarPhysicalEphemeris()
+
 	if (s.function == SpaZaInc) || (s.function == SpaAll) {
 		s.incidence = s.surfaceIncidenceAngle(s.zenith, s.azimuthAstro,
 			s.azmRotation, s.slope)
 	}
 	if (s.function == SpaZaRts) || (s.function == SpaAll) {
 		s.calculateEotAndSunRiseTransitSet()
+		s.dayLengthChange = 60.0 * (s.dayLength - s.previousDayLength())
+		// the rise/transit/set days and the previous day reach beyond the date
+		for i := range s.rtsAlpha {
+			if math.IsNaN(s.rtsAlpha[i]) || math.IsNaN(s.rtsDelta[i]) {
+				return errors.New("date out of the earth ephemeris range")
+			}
+		}
+		if math.IsNaN(s.dayLengthChange) {
+			return errors.New("date out of the earth ephemeris range")
+		}
 	}
 
 	return nil
@@ -1340,7 +1981,6 @@ func (s *spa) earthValues(termSum []float64, count int64, jme float64) (sum floa
 	for i := 0; i < int(count); i++ {
 		sum += termSum[i] * math.Pow(jme, float64(i))
 	}
-	sum /= 1.0e8
 
 	return sum
 }
@@ -1352,7 +1992,7 @@ func (s *spa) earthHeliocentricLongitude(jme float64) float64 {
 
 		sum[i] = s.earthPeriodicTermSummation(LTerms[i], int(lSubcount[i]), jme)
 	}
-	return s.limitDegrees(s.rad2deg(s.earthValues(sum, LCount, jme)))
+	return s.limitDegrees(s.rad2deg(s.earthValues(sum, LCount, jme) / 1.0e8))
 
 }
 
@@ -1364,7 +2004,7 @@ func (s *spa) earthHeliocentricLatitude(jme float64) float64 {
 		sum[i] = s.earthPeriodicTermSummation(BTerms[i], int(bSubcount[i]), jme)
 	}
 
-	return s.rad2deg(s.earthValues(sum, BCount, jme))
+	return s.rad2deg(s.earthValues(sum, BCount, jme) / 1.0e8)
 
 }
 
@@ -1374,7 +2014,7 @@ func (s *spa) earthRadiusVector(jme float64) float64 {
 
 		sum[i] = s.earthPeriodicTermSummation(RTerms[i], int(rSubcount[i]), jme)
 	}
-	return s.earthValues(sum, RCount, jme)
+	return s.earthValues(sum, RCount, jme) / 1.0e8
 
 }
 
@@ -1494,9 +2134,7 @@ func (s *spa) rightAscensionParallaxAndTopocentricDec(latitude float64, elevatio
 	xiRad := s.deg2rad(xi)
 	hRad := s.deg2rad(h)
 	deltaRad := s.deg2rad(delta)
-	u := math.Atan(0.99664719 * math.Tan(latRad))
-	y := 0.99664719*math.Sin(u) + elevation*math.Sin(latRad)/6378140.0
-	x := math.Cos(u) + elevation*math.Cos(latRad)/6378140.0
+	x, y := geocentricPosition(s.ellipsoid, latRad, elevation)
 
 	deltaAlphaRad = math.Atan2(-x*math.Sin(xiRad)*math.Sin(hRad), math.Cos(deltaRad)-x*math.Sin(xiRad)*math.Cos(hRad))
 
@@ -1531,6 +2169,81 @@ func (s *spa) atmosphericRefractionCorrection(pressure float64, temperature floa
 	return delE
 }
 
+// limbRefractionCorrection calculates the refraction of a solar limb [degrees] like atmosphericRefractionCorrection,
+// but without switching the refraction off below the horizon, which would tear the disk apart at sunrise and sunset
+func (s *spa) limbRefractionCorrection(pressure float64, temperature float64, e0 float64) float64 {
+	return (pressure / 1010.0) * (283.0 / (273.0 + temperature)) * 1.02 / (60.0 * math.Tan(s.deg2rad(e0+10.3/(e0+5.11))))
+}
+
+func (s *spa) refractionConditions() RefractionConditions {
+	return RefractionConditions{Pressure: s.localPressure, Temperature: s.localTemperature, Elevation: s.elevation, Height: s.observerHeight, AtmosRefract: s.atmosRefract}
+}
+
+// refraction calculates the atmospheric refraction correction [degrees] of the true elevation e0 [degrees]
+// with the selected refraction model
+func (s *spa) refraction(e0 float64) float64 {
+	if s.observerMode == ObserverSpace {
+		return 0
+	}
+	if s.refractionModel == nil {
+		return s.atmosphericRefractionCorrection(s.localPressure, s.localTemperature, s.atmosRefract, e0)
+	}
+	return s.refractionModel.Refraction(e0, s.refractionConditions())
+}
+
+// limbRefraction calculates the atmospheric refraction correction [degrees] of a solar limb at the true elevation
+// e0 [degrees], the refraction formulas are used without the cut-off and held at their maximum below the horizon
+func (s *spa) limbRefraction(e0 float64) float64 {
+	if s.observerMode == ObserverSpace {
+		return 0
+	}
+	switch m := s.refractionModel.(type) {
+	case nil, SpaRefraction:
+		return SaemundssonRefraction{}.limbRefraction(e0, s.refractionConditions())
+	case SaemundssonRefraction:
+		return m.limbRefraction(e0, s.refractionConditions())
+	case BennettRefraction:
+		return m.limbRefraction(e0, s.refractionConditions())
+	}
+	return s.refractionModel.Refraction(e0, s.refractionConditions())
+}
+
+// limbInverseRefraction calculates the atmospheric refraction correction [degrees] of a solar limb at the apparent
+// elevation e [degrees] with the selected refraction model, without the cut-off like limbRefraction
+func (s *spa) limbInverseRefraction(e float64) float64 {
+	switch m := s.refractionModel.(type) {
+	case SaemundssonRefraction:
+		return iterateInverseRefraction(e, func(e0 float64) float64 {
+			return m.limbRefraction(e0, s.refractionConditions())
+		})
+	case BennettRefraction:
+		return m.apparentRefraction(e, s.refractionConditions())
+	}
+	return s.refractionModel.InverseRefraction(e, s.refractionConditions())
+}
+
+// sunDiskVisibleFraction calculates the fraction of the solar disk area above the horizon elevation [degrees].
+// The refraction lifts the lower limb more than the upper limb, which flattens the disk to an ellipse.
+func (s *spa) sunDiskVisibleFraction(e0 float64, r float64, horizon float64) float64 {
+	radius := s.sunSemiDiameter(r)
+	upper := e0 + radius
+	lower := e0 - radius
+	upper += s.limbRefraction(upper)
+	lower += s.limbRefraction(lower)
+
+	center := (upper + lower) / 2
+	semiAxis := (upper - lower) / 2
+	x := (center - horizon) / semiAxis
+	if x >= 1 {
+		return 1
+	}
+	if x <= -1 {
+		return 0
+	}
+	// circular segment of the (scaled) disk above the horizon line
+	return 1 - (math.Acos(x)-x*math.Sqrt(1-x*x))/math.Pi
+}
+
 func (s *spa) topocentricElevationAngleCorrected(e0 float64, deltaE float64) float64 {
 	return e0 + deltaE
 }
@@ -1586,11 +2299,11 @@ func (s *spa) sunHourAngleAtRiseSet(latitude float64, deltaZero float64, h0Prime
 	return h0
 }
 
-func (s *spa) approxSunRiseAndSet(h0 float64) {
+func (s *spa) approxSunRiseAndSet(mRts []float64, h0 float64) {
 	h0Dfrac := h0 / 360.0
-	s.mRts[SunRise] = s.limitZero2one(s.mRts[SunTransit] - h0Dfrac)
-	s.mRts[SunSet] = s.limitZero2one(s.mRts[SunTransit] + h0Dfrac)
-	s.mRts[SunTransit] = s.limitZero2one(s.mRts[SunTransit])
+	mRts[SunRise] = s.limitZero2one(mRts[SunTransit] - h0Dfrac)
+	mRts[SunSet] = s.limitZero2one(mRts[SunTransit] + h0Dfrac)
+	mRts[SunTransit] = s.limitZero2one(mRts[SunTransit])
 }
 
 func (s *spa) rtsAlphaDeltaPrime(ad []float64, n float64) float64 {
@@ -1615,6 +2328,62 @@ func (s *spa) rtsSunAltitude(latitude float64, deltaPrime float64, hPrime float6
 		math.Cos(latitudeRad)*math.Cos(deltaPrimeRad)*math.Cos(s.deg2rad(hPrime))))
 }
 
+func (s *spa) horizonDipAngle(height float64, dip HorizonDips) float64 {
+	if height <= 0 {
+		return 0
+	}
+	switch dip {
+	case DipGeometric:
+		return s.rad2deg(math.Acos(EarthMeanRadius / (EarthMeanRadius + height)))
+	case DipRefracted:
+		effectiveRadius := EarthMeanRadius / (1 - TerrestrialRefraction)
+		return s.rad2deg(math.Acos(effectiveRadius / (effectiveRadius + height)))
+	}
+	return 0
+}
+
+func (s *spa) sunSemiDiameter(r float64) float64 {
+	return 959.63 / (3600.0 * r)
+}
+
+func (s *spa) horizonRefraction(pressure float64, temperature float64) float64 {
+	return 0.5667 * (pressure / 1010.0) * (283.0 / (273.0 + temperature))
+}
+
+// riseSetSunAltitude calculates the altitude of the sun center at sunrise and sunset (h0Prime) [degrees]
+func (s *spa) riseSetSunAltitude() float64 {
+	var radius float64
+	refraction := s.atmosRefract
+	if s.observerMode == ObserverSpace {
+		refraction = 0
+	} else {
+		if s.atmosRefractFromWeather || s.meteorology != nil {
+			refraction = s.horizonRefraction(s.localPressure, s.localTemperature)
+		}
+		switch s.refractionModel.(type) {
+		case nil, SpaRefraction:
+		default:
+			// refraction at the apparent horizon, lowered by the horizon dip
+			refraction = s.limbInverseRefraction(-s.dip)
+		}
+	}
+
+	switch s.riseSetDefinition {
+	case RiseSetUpperLimb:
+		radius = s.sunSemiDiameter(s.r)
+	case RiseSetCenter:
+		radius = 0
+	case RiseSetLowerLimb:
+		radius = -s.sunSemiDiameter(s.r)
+	case RiseSetCustomDisk:
+		radius = s.diskFraction * s.sunSemiDiameter(s.r)
+	default:
+		radius = SunRadius
+	}
+
+	return -1*(radius+refraction) - s.dip
+}
+
 func (s *spa) sunRiseAndSet(mRts []float64, hRts []float64, deltaPrime []float64, latitude float64, hPrime []float64, h0Prime float64, sun int) float64 {
 	return mRts[sun] + (hRts[sun]-h0Prime)/
 		(360.0*math.Cos(s.deg2rad(deltaPrime[sun]))*math.Cos(s.deg2rad(latitude))*math.Sin(s.deg2rad(hPrime[sun])))
@@ -1633,9 +2402,13 @@ func (s *spa) calculateGeocentricSunRightAscensionAndDeclination() {
 	s.jce = s.julianEphemerisCentury(s.jde)
 	s.jme = s.julianEphemerisMillennium(s.jce)
 
-	s.l = s.earthHeliocentricLongitude(s.jme)
-	s.b = s.earthHeliocentricLatitude(s.jme)
-	s.r = s.earthRadiusVector(s.jme)
+	if s.earthEphemeris != nil {
+		s.l, s.b, s.r = s.earthEphemeris.EarthPosition(s.jme)
+	} else {
+		s.l = s.earthHeliocentricLongitude(s.jme)
+		s.b = s.earthHeliocentricLatitude(s.jme)
+		s.r = s.earthRadiusVector(s.jme)
+	}
 
 	s.theta = s.geocentricLongitude(s.l)
 	s.beta = s.geocentricLatitude(s.b)
@@ -1646,7 +2419,11 @@ func (s *spa) calculateGeocentricSunRightAscensionAndDeclination() {
 	x[TermX3], s.x3 = s.argumentLatitudeMoon(s.jce), s.argumentLatitudeMoon(s.jce)
 	x[TermX4], s.x4 = s.ascendingLongitudeMoon(s.jce), s.ascendingLongitudeMoon(s.jce)
 
-	s.nutationLongitudeAndObliquity(s.jce, x)
+	if s.nutationModel == NutationIAU2000B {
+		s.nutationIAU2000B(s.jce)
+	} else {
+		s.nutationLongitudeAndObliquity(s.jce, x)
+	}
 
 	s.epsilon0 = s.eclipticMeanObliquity(s.jme)
 	s.epsilon = s.eclipticTrueObliquity(s.delEpsilon, s.epsilon0)
@@ -1667,19 +2444,11 @@ func (s *spa) calculateGeocentricSunRightAscensionAndDeclination() {
 func (s *spa) calculateEotAndSunRiseTransitSet() {
 	//spa_data
 	// sun_rts
-	var nu, m, h0, n float64
+	var m float64
 	alpha := make([]float64, JdCount)
 	delta := make([]float64, JdCount)
 
-	nuRts := make([]float64, SunCount)
-	hRts := make([]float64, SunCount)
-	alphaPrime := make([]float64, SunCount)
-	deltaPrime := make([]float64, SunCount)
-	hPrime := make([]float64, SunCount)
-	h0Prime := -1 * (SunRadius + s.atmosRefract)
-
 	sunRts := *s
-	sunRts.mRts = make([]float64, SunCount)
 	m = s.sunMeanLongitude(s.jme)
 	s.eot = s.eotf(m, s.alpha, s.delPsi, s.epsilon)
 
@@ -1693,7 +2462,7 @@ func (s *spa) calculateEotAndSunRiseTransitSet() {
 		sunRts.minute, sunRts.second, sunRts.deltaUt1, sunRts.timezone)
 
 	sunRts.calculateGeocentricSunRightAscensionAndDeclination()
-	nu = sunRts.nu
+	s.rtsNu = sunRts.nu
 
 	sunRts.deltaT = 0
 	sunRts.jd--
@@ -1703,43 +2472,121 @@ func (s *spa) calculateEotAndSunRiseTransitSet() {
 		delta[i] = sunRts.delta
 		sunRts.jd++
 	}
+	s.rtsAlpha = alpha
+	s.rtsDelta = delta
 
-	sunRts.mRts[SunTransit] = s.approxSunTransitTime(alpha[JdZero], s.longitude, nu)
-	h0 = s.sunHourAngleAtRiseSet(s.latitude, delta[JdZero], h0Prime)
+	s.h0Prime = s.riseSetSunAltitude()
+	h0Prime := s.h0Prime
 
-	if h0 >= 0 {
+	rts, ok := s.sunRiseTransitSet(h0Prime)
+	if ok && s.meteorology != nil {
+		rts = s.meteorologyRiseSet(rts)
+	}
+	s.mRts = rts.mRts
+	if ok {
+		s.srha = rts.hPrime[SunRise]
+		s.ssha = rts.hPrime[SunSet]
+		s.sta = rts.hRts[SunTransit]
+		s.suntransit = rts.transit
+		s.sunrise = rts.rise
+		s.sunset = rts.set
+		s.sunriseAzimuth = rts.riseAzimuth
+		s.sunsetAzimuth = rts.setAzimuth
+		s.dayLength = rts.length
+	} else {
+		s.srha, s.ssha, s.sta, s.suntransit, s.sunrise, s.sunset = -99999, -99999, -99999, -99999, -99999, -99999
+		s.sunriseAzimuth, s.sunsetAzimuth = -99999, -99999
+		// polar day or polar night
+		s.dayLength = 0
+		if 90.0-math.Abs(s.latitude-delta[JdZero]) > h0Prime {
+			s.dayLength = 24
+		}
+	}
 
-		sunRts.approxSunRiseAndSet(h0)
-		for i := 0; i < SunCount; i++ {
+}
 
-			nuRts[i] = nu + 360.985647*sunRts.mRts[i]
+// riseTransitSet holds the interpolated values of a rise, transit and set calculation
+type riseTransitSet struct {
+	mRts       []float64 // fractional day of rise, transit and set
+	hRts       []float64 // sun altitude at rise, transit and set [degrees]
+	deltaPrime []float64 // sun declination at rise, transit and set [degrees]
+	hPrime     []float64 // local hour angle at rise, transit and set [degrees]
 
-			n = sunRts.mRts[i] + sunRts.deltaT/86400.0
-			alphaPrime[i] = sunRts.rtsAlphaDeltaPrime(alpha, n)
-			deltaPrime[i] = sunRts.rtsAlphaDeltaPrime(delta, n)
+	transit float64 // local transit time [fractional hour]
+	rise    float64 // local rise time [fractional hour]
+	set     float64 // local set time [fractional hour]
+	length  float64 // duration from rise to set [fractional hour]
 
-			hPrime[i] = s.limitDegrees180pm(nuRts[i] + s.longitude - alphaPrime[i])
+	riseAzimuth float64 // topocentric azimuth angle at rise (eastward from north) [degrees]
+	setAzimuth  float64 // topocentric azimuth angle at set (eastward from north) [degrees]
+}
 
-			hRts[i] = s.rtsSunAltitude(s.latitude, deltaPrime[i], hPrime[i])
-		}
+////////////////////////////////////////////////////////////////////////////////////////////////
+// Calculate the rise, transit and set of the sun center at elevation h0Prime [degrees]
+// Note: rtsAlpha, rtsDelta and rtsNu must be already calculated and in structure
+////////////////////////////////////////////////////////////////////////////////////////////////
+func (s *spa) sunRiseTransitSet(h0Prime float64) (rts riseTransitSet, ok bool) {
+	var h0, n float64
+	nuRts := make([]float64, SunCount)
+	alphaPrime := make([]float64, SunCount)
+	rts.mRts = make([]float64, SunCount)
+	rts.hRts = make([]float64, SunCount)
+	rts.deltaPrime = make([]float64, SunCount)
+	rts.hPrime = make([]float64, SunCount)
 
-		s.srha = hPrime[SunRise]
-		s.ssha = hPrime[SunSet]
-		s.sta = hRts[SunTransit]
+	rts.mRts[SunTransit] = s.approxSunTransitTime(s.rtsAlpha[JdZero], s.longitude, s.rtsNu)
+	h0 = s.sunHourAngleAtRiseSet(s.latitude, s.rtsDelta[JdZero], h0Prime)
 
-		s.suntransit = s.dayfracToLocalHr(sunRts.mRts[SunTransit]-hPrime[SunTransit]/360.0,
-			s.timezone)
+	if h0 < 0 {
+		return rts, false
+	}
 
-		s.sunrise = s.dayfracToLocalHr(s.sunRiseAndSet(sunRts.mRts, hRts, deltaPrime,
-			s.latitude, hPrime, h0Prime, SunRise), s.timezone)
+	s.approxSunRiseAndSet(rts.mRts, h0)
+	for i := 0; i < SunCount; i++ {
 
-		s.sunset = s.dayfracToLocalHr(s.sunRiseAndSet(sunRts.mRts, hRts, deltaPrime,
-			s.latitude, hPrime, h0Prime, SunSet), s.timezone)
+		nuRts[i] = s.rtsNu + 360.985647*rts.mRts[i]
 
-	} else {
-		s.srha, s.ssha, s.sta, s.suntransit, s.sunrise, s.sunset = -99999, -99999, -99999, -99999, -99999, -99999
+		n = rts.mRts[i]
+		alphaPrime[i] = s.rtsAlphaDeltaPrime(s.rtsAlpha, n)
+		rts.deltaPrime[i] = s.rtsAlphaDeltaPrime(s.rtsDelta, n)
+
+		rts.hPrime[i] = s.limitDegrees180pm(nuRts[i] + s.longitude - alphaPrime[i])
+
+		rts.hRts[i] = s.rtsSunAltitude(s.latitude, rts.deltaPrime[i], rts.hPrime[i])
 	}
 
+	rts.transit = s.dayfracToLocalHr(rts.mRts[SunTransit]-rts.hPrime[SunTransit]/360.0,
+		s.timezone)
+
+	rise := s.sunRiseAndSet(rts.mRts, rts.hRts, rts.deltaPrime, s.latitude, rts.hPrime, h0Prime, SunRise)
+	set := s.sunRiseAndSet(rts.mRts, rts.hRts, rts.deltaPrime, s.latitude, rts.hPrime, h0Prime, SunSet)
+
+	rts.rise = s.dayfracToLocalHr(rise, s.timezone)
+	rts.set = s.dayfracToLocalHr(set, s.timezone)
+	rts.length = 24.0 * s.limitZero2one(set-rise)
+
+	rts.riseAzimuth = s.riseSetAzimuth(rts.rise)
+	rts.setAzimuth = s.riseSetAzimuth(rts.set)
+
+	return rts, true
+}
+
+// riseSetAzimuth calculates the azimuth (eastward from north) [degrees] of the sun at the corrected local rise
+// or set hour of the day, which can fall on the previous or next UT day
+func (s *spa) riseSetAzimuth(hour float64) float64 {
+	m := (hour - s.timezone) / 24.0
+	alpha := s.rtsAlphaDeltaPrime(s.rtsAlpha, m)
+	delta := s.rtsAlphaDeltaPrime(s.rtsDelta, m)
+	h := s.limitDegrees180pm(s.rtsNu + 360.985647*m + s.longitude - alpha)
+	return s.topocentricAzimuthAngle(s.topocentricAzimuthAngleAstro(h, s.latitude, delta))
+}
+
+// previousDayLength calculates the daylight duration of the previous day [fractional hour]
+func (s *spa) previousDayLength() float64 {
+	previous := *s
+	previous.SetDate(s.GetDate().AddDate(0, 0, -1))
+	previous.calculateEotAndSunRiseTransitSet()
+	return previous.dayLength
 }
 
 func (s *spa) validate() error {
@@ -1761,11 +2608,14 @@ func (s *spa) validate() error {
 	if (s.second < 0) || (s.second >= 60) {
 		return errors.New("invalid second")
 	}
-	if (s.pressure < 0) || (s.pressure > 5000) {
-		return errors.New("invalid pressure")
-	}
-	if (s.temperature <= -273) || (s.temperature > 6000) {
-		return errors.New("invalid temperature")
+	// the weather is not used without refraction in space
+	if s.observerMode != ObserverSpace {
+		if math.IsNaN(s.localPressure) || (s.localPressure < 0) || (s.localPressure > 5000) {
+			return errors.New("invalid pressure")
+		}
+		if math.IsNaN(s.localTemperature) || (s.localTemperature <= -273) || (s.localTemperature > 6000) {
+			return errors.New("invalid temperature")
+		}
 	}
 	if (s.deltaUt1 <= -1) || (s.deltaUt1 >= 1) {
 		return errors.New("invalid UTC / UT difference (deltaUt1)")
@@ -1795,6 +2645,18 @@ func (s *spa) validate() error {
 	if s.elevation < -6500000 {
 		return errors.New("invalid elevation")
 	}
+	if s.observerHeight < 0 {
+		return errors.New("invalid observer height")
+	}
+	if math.Abs(s.diskFraction) > 1 {
+		return errors.New("invalid disk fraction")
+	}
+	if (math.Abs(s.xp) > 1) || (math.Abs(s.yp) > 1) {
+		return errors.New("invalid polar motion")
+	}
+	if series, ok := s.earthEphemeris.(*VSOP87); ok && series.GetBody() != "EARTH" {
+		return errors.New("VSOP87 earth ephemeris of another body")
+	}
 
 	if (s.function == SpaZaInc) || (s.function == SpaAll) {
 		if math.Abs(s.slope) > 360 {
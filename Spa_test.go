@@ -0,0 +1,242 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// newTestSpa creates the instance of the NREL SPA example (Golden, Colorado, 17 October 2003)
+func newTestSpa(t *testing.T) Spa {
+	t.Helper()
+	s, err := NewSpa(time.Date(2003, 10, 17, 12, 30, 30, 0, time.FixedZone("", -7*3600)),
+		39.742476, -105.1786, 1830.14, 820, 11, 67, 0, 30, -10, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSpaceObserverDefaultedWeather(t *testing.T) {
+	s, err := NewSpa(time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC),
+		0, 0, 400000, math.NaN(), math.NaN(), 69, 0, 0, 0, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetObserverMode(ObserverSpace)
+	s.SetPressure(math.NaN())
+	s.SetTemperature(math.NaN())
+	if err := s.Calculate(); err != nil {
+		t.Fatalf("400 km observer: %v", err)
+	}
+	if s.GetDelE() != 0 || s.GetE() != s.GetE0() {
+		t.Errorf("refraction in space: got %v", s.GetDelE())
+	}
+	// limb depression acos(R / (R + h)) with the IAU 1976 equatorial radius
+	want := 180 / math.Pi * math.Acos(6378140.0/(6378140.0+400000.0))
+	if math.Abs(s.GetLimbDepression()-want) > 1e-6 {
+		t.Errorf("limb depression: got %v, want %v", s.GetLimbDepression(), want)
+	}
+	if s.GetDip() != s.GetLimbDepression() {
+		t.Errorf("horizon dip: got %v, want the limb depression %v", s.GetDip(), s.GetLimbDepression())
+	}
+}
+
+// a balloon at 30 km sees the sun rise and set at the earth limb
+func TestSpaceObserverRiseSet(t *testing.T) {
+	s := newTestSpa(t)
+	s.SetObserverMode(ObserverSpace)
+	s.SetAltitudeKm(30)
+	if s.GetElevation() != 30000 || s.GetAltitudeKm() != 30 {
+		t.Errorf("got elevation %v m and altitude %v km, want 30000 and 30", s.GetElevation(), s.GetAltitudeKm())
+	}
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if want := -SunRadius - s.GetLimbDepression(); math.Abs(s.GetH0Prime()-want) > 1e-12 {
+		t.Errorf("h0': got %v, want %v", s.GetH0Prime(), want)
+	}
+	ground := newTestSpa(t)
+	if err := ground.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.GetSunrise().Before(ground.GetSunrise()) || !s.GetSunset().After(ground.GetSunset()) {
+		t.Errorf("got %v to %v, want around %v to %v", s.GetSunrise(), s.GetSunset(), ground.GetSunrise(), ground.GetSunset())
+	}
+	for _, event := range []time.Time{s.GetSunrise(), s.GetSunset()} {
+		sun := newTestSpa(t)
+		sun.SetObserverMode(ObserverSpace)
+		sun.SetAltitudeKm(30)
+		sun.SetSPAFunction(SpaZa)
+		sun.SetDate(event)
+		if err := sun.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		// the interpolated set time of the SPA is good to about a minute, 0.3 degrees of the sun elevation
+		if math.Abs(sun.GetE()-s.GetH0Prime()) > 0.3 {
+			t.Errorf("sun at %v: got %v, want %v", event.Format("15:04:05"), sun.GetE(), s.GetH0Prime())
+		}
+	}
+}
+
+// the rise and set azimuths match the sun position at the rise and set times
+func TestRiseSetAzimuth(t *testing.T) {
+	for _, date := range []time.Time{
+		time.Date(2003, 10, 17, 12, 0, 0, 0, time.FixedZone("", -7*3600)),
+		time.Date(2024, 6, 20, 12, 0, 0, 0, time.FixedZone("", -7*3600)),
+		time.Date(2024, 12, 21, 12, 0, 0, 0, time.FixedZone("", -7*3600)),
+	} {
+		s := newTestSpa(t)
+		s.SetDate(date)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range []struct {
+			name    string
+			time    time.Time
+			azimuth float64
+		}{{"sunrise", s.GetSunrise(), s.GetSunriseAzimuth()}, {"sunset", s.GetSunset(), s.GetSunsetAzimuth()}} {
+			sun := newTestSpa(t)
+			sun.SetSPAFunction(SpaZa)
+			sun.SetDate(event.time)
+			if err := sun.Calculate(); err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(sun.GetAzimuth()-event.azimuth) > 0.01 {
+				t.Errorf("%v %v: azimuth %v, sun azimuth at %v is %v", date.Format("2006-01-02"), event.name,
+					event.azimuth, event.time.Format("15:04:05"), sun.GetAzimuth())
+			}
+		}
+	}
+}
+
+// the selected refraction model sets the rise and set altitude without the weather options
+func TestRiseSetRefractionModel(t *testing.T) {
+	want := newTestSpa(t)
+	c := want.(*spa).refractionConditions()
+	tests := []struct {
+		model RefractionModel
+		h0    float64
+	}{
+		{nil, -(SunRadius + 0.5667)},
+		{SpaRefraction{}, -(SunRadius + 0.5667)},
+		{NoRefraction{}, -SunRadius},
+		{BennettRefraction{}, -(SunRadius + BennettRefraction{}.apparentRefraction(0, c))},
+		{RayTraceRefraction{}, -(SunRadius + RayTraceRefraction{}.InverseRefraction(0, c))},
+	}
+	for _, test := range tests {
+		s := newTestSpa(t)
+		s.SetRefractionModel(test.model)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(s.GetH0Prime()-test.h0) > 1e-9 {
+			t.Errorf("%T: got h0' %v, want %v", test.model, s.GetH0Prime(), test.h0)
+		}
+		// a lower refraction delays the sunrise and advances the sunset
+		if test.h0 > want.GetH0Prime() && !(s.GetSunrise().After(want.GetSunrise()) && s.GetSunset().Before(want.GetSunset())) {
+			t.Errorf("%T: got sunrise %v and sunset %v, want inside %v to %v", test.model,
+				s.GetSunrise(), s.GetSunset(), want.GetSunrise(), want.GetSunset())
+		}
+	}
+}
+
+// the geometric dip of a 1000 m observer advances the sunrise and delays the sunset by about 5 minutes
+func TestHorizonDip(t *testing.T) {
+	want := newTestSpa(t)
+	if err := want.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if want.GetDip() != 0 {
+		t.Errorf("default dip: got %v, want 0", want.GetDip())
+	}
+
+	s := newTestSpa(t)
+	s.SetObserverHeight(1000)
+	s.SetHorizonDip(DipGeometric)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(s.GetDip()-1.015) > 0.001 {
+		t.Errorf("geometric dip: got %v, want 1.015", s.GetDip())
+	}
+	if math.Abs(s.GetH0Prime()-(want.GetH0Prime()-s.GetDip())) > 1e-12 {
+		t.Errorf("h0': got %v, want %v", s.GetH0Prime(), want.GetH0Prime()-s.GetDip())
+	}
+	for _, event := range []struct {
+		name  string
+		shift time.Duration
+	}{
+		{"sunrise", want.GetSunrise().Sub(s.GetSunrise())},
+		{"sunset", s.GetSunset().Sub(want.GetSunset())},
+	} {
+		if event.shift < 4*time.Minute || event.shift > 6*time.Minute {
+			t.Errorf("%v: got %v earlier, want about 5 minutes", event.name, event.shift)
+		}
+	}
+
+	// terrestrial refraction lowers the dip
+	s.SetHorizonDip(DipRefracted)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if refracted := 180 / math.Pi * math.Acos(1-1000*(1-TerrestrialRefraction)/EarthMeanRadius); math.Abs(s.GetDip()-refracted) > 0.001 {
+		t.Errorf("refracted dip: got %v, want about %v", s.GetDip(), refracted)
+	}
+}
+
+// the rise and set definitions select the point of the solar disk on the horizon
+func TestRiseSetDefinition(t *testing.T) {
+	s := newTestSpa(t)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	radius := s.GetSunSemiDiameter()
+	tests := []struct {
+		definition RiseSetDefinitions
+		fraction   float64
+		h0         float64
+	}{
+		{RiseSetUpperLimb, 0, -(radius + 0.5667)},
+		{RiseSetCustomDisk, 0.5, -(0.5*radius + 0.5667)},
+		{RiseSetCenter, 0, -0.5667},
+		{RiseSetCustomDisk, -0.5, -(-0.5*radius + 0.5667)},
+		{RiseSetLowerLimb, 0, -(-radius + 0.5667)},
+	}
+	var previous Spa
+	for _, test := range tests {
+		s := newTestSpa(t)
+		s.SetRiseSetDefinition(test.definition)
+		s.SetDiskFraction(test.fraction)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(s.GetH0Prime()-test.h0) > 1e-9 {
+			t.Errorf("%v %v: got h0' %v, want %v", test.definition, test.fraction, s.GetH0Prime(), test.h0)
+		}
+		// a lower point of the disk rises later and sets earlier
+		if previous != nil && !(s.GetSunrise().After(previous.GetSunrise()) && s.GetSunset().Before(previous.GetSunset())) {
+			t.Errorf("%v %v: got sunrise %v and sunset %v, want inside %v to %v", test.definition, test.fraction,
+				s.GetSunrise(), s.GetSunset(), previous.GetSunrise(), previous.GetSunset())
+		}
+		previous = s
+	}
+
+	// the custom disk at the limbs and the center matches the fixed definitions
+	for fraction, definition := range map[float64]RiseSetDefinitions{1: RiseSetUpperLimb, 0: RiseSetCenter, -1: RiseSetLowerLimb} {
+		custom, want := newTestSpa(t), newTestSpa(t)
+		custom.SetRiseSetDefinition(RiseSetCustomDisk)
+		custom.SetDiskFraction(fraction)
+		want.SetRiseSetDefinition(definition)
+		if err := custom.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		if err := want.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		if !custom.GetSunrise().Equal(want.GetSunrise()) || !custom.GetSunset().Equal(want.GetSunset()) {
+			t.Errorf("custom disk %v: got %v to %v, want %v to %v", fraction,
+				custom.GetSunrise(), custom.GetSunset(), want.GetSunrise(), want.GetSunset())
+		}
+	}
+}
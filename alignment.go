@@ -0,0 +1,126 @@
+package spa
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Alignment defines an instant when the sun crosses the target elevation along the target azimuth
+type Alignment struct {
+	Time      time.Time // local time of the crossing
+	Azimuth   float64   // topocentric azimuth angle (eastward from north) [degrees]
+	Elevation float64   // topocentric elevation angle (corrected) [degrees]
+	Rising    bool      // true for the morning crossing, false for the evening crossing
+}
+
+// calculateAlignments searches the local days from start to end for the crossings of the elevation
+// [degrees] within the tolerance [degrees] of the azimuth [degrees] (eastward from north)
+func (s *spa) calculateAlignments(azimuth float64, elevation float64, tolerance float64, start time.Time, end time.Time) ([]Alignment, error) {
+	var alignments []Alignment
+	if tolerance < 0 || tolerance > 180 {
+		return nil, errors.New("invalid azimuth tolerance")
+	}
+	if end.Before(start) {
+		return nil, errors.New("invalid date range")
+	}
+	azimuth = s.limitDegrees(azimuth)
+
+	zone := time.FixedZone("ManualTimeZone", int(s.timezone*3600))
+	first := start.In(zone)
+	last := end.In(zone)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 12, 0, 0, 0, zone)
+	for !day.After(last.Add(12 * time.Hour)) {
+		sun := *s
+		sun.function = SpaZaRts
+		sun.setLocalDate(day)
+		if err := sun.Calculate(); err != nil {
+			return nil, err
+		}
+		target := elevation
+		if math.IsNaN(target) {
+			target = sun.horizonElevation(azimuth)
+		}
+
+		// geometric elevation of the crossing for the rise/set interpolation
+		rts, ok := sun.sunRiseTransitSet(sun.GetTrueElevation(target))
+		if ok {
+			candidates := []struct {
+				hour    float64
+				azimuth float64
+				rising  bool
+			}{{rts.rise, rts.riseAzimuth, true}, {rts.set, rts.setAzimuth, false}}
+			for _, candidate := range candidates {
+				// skip crossings far off the target azimuth
+				if math.Abs(s.limitDegrees180pm(candidate.azimuth-azimuth)) > tolerance+2 {
+					continue
+				}
+				alignment, found, err := sun.refineCrossing(candidate.hour, target)
+				if err != nil {
+					return nil, err
+				}
+				if !found || math.Abs(s.limitDegrees180pm(alignment.Azimuth-azimuth)) > tolerance {
+					continue
+				}
+				if alignment.Time.Before(start) || alignment.Time.After(end) {
+					continue
+				}
+				alignment.Rising = candidate.rising
+				alignments = append(alignments, alignment)
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return alignments, nil
+}
+
+// refineCrossing bisects the instant near the local hour when the corrected elevation crosses the target [degrees]
+func (s *spa) refineCrossing(hour float64, target float64) (Alignment, bool, error) {
+	var alignment Alignment
+	dayStart := s.daySecondToDate(0)
+	position := func(daySecond int) (spa, error) {
+		sun := *s
+		sun.function = SpaZa
+		sun.setLocalDate(dayStart.Add(time.Duration(daySecond) * time.Second))
+		err := sun.Calculate()
+		return sun, err
+	}
+
+	guess := int(hour * 3600)
+	for _, window := range []int{1800, 7200} {
+		low, high := guess-window, guess+window
+		lowSun, err := position(low)
+		if err != nil {
+			return alignment, false, err
+		}
+		highSun, err := position(high)
+		if err != nil {
+			return alignment, false, err
+		}
+		lowAbove := lowSun.e > target
+		if lowAbove == (highSun.e > target) {
+			continue
+		}
+		for high-low > 1 {
+			mid := (low + high) / 2
+			midSun, err := position(mid)
+			if err != nil {
+				return alignment, false, err
+			}
+			if (midSun.e > target) == lowAbove {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		sun, err := position(high)
+		if err != nil {
+			return alignment, false, err
+		}
+		alignment.Time = sun.GetDate()
+		alignment.Azimuth = sun.azimuth
+		alignment.Elevation = sun.e
+		return alignment, true, nil
+	}
+	return alignment, false, nil
+}
@@ -0,0 +1,46 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Manhattanhenge 2024 of the American Museum of Natural History: the sun sets along the street grid
+// (azimuth 299 degrees) above the New Jersey horizon, about 0.55 degrees high
+func TestManhattanhenge(t *testing.T) {
+	edt := time.FixedZone("EDT", -4*3600)
+	s, err := NewSpa(time.Date(2024, 5, 28, 12, 0, 0, 0, edt), 40.758, -73.9855, 10, 1010, 20, 69, 0, 0, 0, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		elevation float64 // sun center [degrees]
+		want      []time.Time
+	}{
+		{"half sun", 0.55, []time.Time{time.Date(2024, 5, 28, 20, 13, 0, 0, edt), time.Date(2024, 7, 13, 20, 21, 0, 0, edt)}},
+		{"full sun", 0.55 + 0.26, []time.Time{time.Date(2024, 5, 29, 20, 12, 0, 0, edt), time.Date(2024, 7, 12, 20, 20, 0, 0, edt)}},
+	}
+	for _, test := range tests {
+		alignments, err := s.FindAlignments(299, test.elevation, 0.15,
+			time.Date(2024, 5, 1, 0, 0, 0, 0, edt), time.Date(2024, 8, 1, 0, 0, 0, 0, edt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, alignment := range alignments {
+			if alignment.Rising || math.Abs(alignment.Azimuth-299) > 0.15 || math.Abs(alignment.Elevation-test.elevation) > 0.01 {
+				t.Errorf("%v: got %+v", test.name, alignment)
+			}
+		}
+		for _, want := range test.want {
+			found := false
+			for _, alignment := range alignments {
+				found = found || math.Abs(alignment.Time.Sub(want).Minutes()) < 2
+			}
+			if !found {
+				t.Errorf("%v: no alignment at %v, got %+v", test.name, want, alignments)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package spa
+
+import "math"
+
+// standard atmosphere layers (U.S. Standard Atmosphere 1976, ICAO up to 32 km): base geopotential height [meters]
+// and temperature lapse rate [K/m]
+var standardAtmosphereLayers = [][2]float64{
+	{0, 0.0065},
+	{11000, 0},
+	{20000, -0.001},
+	{32000, -0.0028},
+	{47000, 0},
+	{51000, 0.0028},
+	{71000, 0.002},
+	{84852, 0},
+}
+
+// standard atmosphere sea level values and earth radius for the geopotential height
+const (
+	standardPressure    = 1013.25  // sea level pressure [millibars]
+	standardTemperature = 288.15   // sea level temperature [K]
+	geopotentialRadius  = 6356766. // earth radius of the geopotential height [meters]
+	standardMinHeight   = -5000.0  // lowest tabulated height of the standard atmosphere [meters]
+	mesopauseHeight     = 84852.0  // geopotential height of the 86 km geometric altitude [meters]
+	mesopauseTemp       = 186.87   // temperature above 86 km [K]
+)
+
+// StandardAtmosphere calculates the pressure [millibars] and temperature [degrees Celsius] of the
+// U.S. Standard Atmosphere 1976 at the elevation [meters], which is identical to the ICAO standard
+// atmosphere below 32 km. Elevations below -5000 meters are treated as -5000 meters, above 86 km the
+// temperature is held at 186.87 K and the pressure falls exponentially towards zero.
+func StandardAtmosphere(elevation float64) (pressure float64, temperature float64) {
+	elevation = math.Max(elevation, standardMinHeight)
+	height := geopotentialRadius * elevation / (geopotentialRadius + elevation)
+
+	pressure = standardPressure
+	temperature = standardTemperature
+	for i, layer := range standardAtmosphereLayers {
+		base, lapse := layer[0], layer[1]
+		if i > 0 && height <= base {
+			break
+		}
+		top := height
+		if i+1 < len(standardAtmosphereLayers) {
+			top = math.Min(height, standardAtmosphereLayers[i+1][0])
+		}
+		if lapse == 0 {
+			pressure *= math.Exp(-hydrostaticConstant * (top - base) / temperature)
+		} else {
+			next := temperature - lapse*(top-base)
+			pressure *= math.Pow(next/temperature, hydrostaticConstant/lapse)
+			temperature = next
+		}
+	}
+	if height > mesopauseHeight {
+		temperature = mesopauseTemp
+	}
+	return pressure, temperature - 273.15
+}
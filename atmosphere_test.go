@@ -0,0 +1,85 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// U.S. Standard Atmosphere 1976 table values of the geometric altitude
+func TestStandardAtmosphere(t *testing.T) {
+	tests := []struct {
+		elevation   float64 // [meters]
+		pressure    float64 // [millibars]
+		temperature float64 // [K]
+	}{
+		{0, 1013.25, 288.15},
+		{1000, 898.76, 281.65},
+		{11000, 226.99, 216.77},
+		{20000, 55.29, 216.65},
+		{32000, 8.889, 228.49},
+		{50000, 0.7978, 270.65},
+		{86000, 0.003734, 186.87},
+	}
+	for _, test := range tests {
+		pressure, temperature := StandardAtmosphere(test.elevation)
+		if math.Abs(pressure-test.pressure) > 1e-3*test.pressure {
+			t.Errorf("pressure at %v m: got %v, want %v", test.elevation, pressure, test.pressure)
+		}
+		if math.Abs(temperature+273.15-test.temperature) > 0.05 {
+			t.Errorf("temperature at %v m: got %v K, want %v K", test.elevation, temperature+273.15, test.temperature)
+		}
+	}
+}
+
+func TestStandardAtmosphereAboveMesopause(t *testing.T) {
+	previous, _ := StandardAtmosphere(86000)
+	for _, elevation := range []float64{100000, 150000, 200000, 400000, 1000000} {
+		pressure, temperature := StandardAtmosphere(elevation)
+		if math.IsNaN(pressure) || pressure < 0 || pressure >= previous {
+			t.Errorf("pressure at %v m: got %v, want between 0 and %v", elevation, pressure, previous)
+		}
+		if math.Abs(temperature+273.15-186.87) > 1e-9 {
+			t.Errorf("temperature at %v m: got %v K, want 186.87 K", elevation, temperature+273.15)
+		}
+		previous = pressure
+	}
+}
+
+// missing weather inputs are derived from the elevation for the calculation and stay unset
+func TestDefaultedWeather(t *testing.T) {
+	s, err := NewSpa(time.Date(2003, 10, 17, 12, 30, 30, 0, time.FixedZone("", -7*3600)),
+		39.742476, -105.1786, 1830.14, math.NaN(), math.NaN(), 67, 0, 30, -10, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(s.GetPressure()) || !math.IsNaN(s.GetTemperature()) {
+		t.Errorf("inputs overwritten: got %v mbar %v C, want NaN", s.GetPressure(), s.GetTemperature())
+	}
+	for _, elevation := range []float64{1830.14, 4000} {
+		s.SetElevation(elevation)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		pressure, temperature := StandardAtmosphere(elevation)
+		if got := s.(*spa); got.localPressure != pressure || got.localTemperature != temperature {
+			t.Errorf("%v m: got %v mbar %v C, want %v mbar %v C", elevation, got.localPressure, got.localTemperature, pressure, temperature)
+		}
+	}
+}
+
+// the initial weather is derived from the elevation like the math.NaN() inputs of NewSpa
+func TestInitWeather(t *testing.T) {
+	var s spa
+	s.init()
+	if !s.GetPressureDefaulted() || !s.GetTemperatureDefaulted() {
+		t.Errorf("initial weather is not derived from the elevation")
+	}
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	pressure, temperature := StandardAtmosphere(s.elevation)
+	if s.localPressure != pressure || s.localTemperature != temperature {
+		t.Errorf("got %v mbar %v C, want %v mbar %v C", s.localPressure, s.localTemperature, pressure, temperature)
+	}
+}
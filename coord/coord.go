@@ -0,0 +1,143 @@
+// Package coord converts positions between the ecliptic, equatorial (right ascension and hour angle)
+// and horizontal coordinate systems for any celestial body.
+//
+// The conversions use the true obliquity of the ecliptic and the apparent Greenwich sidereal time of a
+// calculated spa.Spa instance, so catalog positions of stars and planets follow the nutation model and
+// conventions of the SPA. Horizontal coordinates are geocentric, the parallax and the atmospheric
+// refraction are not applied. All angles are in degrees.
+package coord
+
+import (
+	"math"
+
+	spa "github.com/maltegrosse/go-spa"
+)
+
+// Frame holds the obliquity, the sidereal time and the observer location of an instant
+type Frame struct {
+	Epsilon   float64 // true obliquity of the ecliptic [degrees]
+	Nu        float64 // Greenwich apparent sidereal time [degrees]
+	Latitude  float64 // observer latitude (negative south of equator) [degrees]
+	Longitude float64 // observer longitude (negative west of Greenwich) [degrees]
+}
+
+// Ecliptic defines a position by the ecliptic longitude and latitude
+type Ecliptic struct {
+	Longitude float64 // ecliptic longitude [degrees]
+	Latitude  float64 // ecliptic latitude [degrees]
+}
+
+// Equatorial defines a position by the right ascension and declination
+type Equatorial struct {
+	RightAscension float64 // right ascension [degrees]
+	Declination    float64 // declination [degrees]
+}
+
+// HourAngle defines a position by the local hour angle and declination
+type HourAngle struct {
+	HourAngle   float64 // local hour angle (westward from the meridian) [degrees]
+	Declination float64 // declination [degrees]
+}
+
+// Horizontal defines a position by the azimuth and elevation
+type Horizontal struct {
+	Azimuth   float64 // azimuth angle (eastward from north) [degrees]
+	Elevation float64 // elevation angle [degrees]
+}
+
+// NewFrame creates the frame of a calculated spa.Spa instance
+func NewFrame(s spa.Spa) Frame {
+	return Frame{
+		Epsilon:   s.GetEpsilon(),
+		Nu:        s.GetNu(),
+		Latitude:  s.GetLatitude(),
+		Longitude: s.GetLongitude(),
+	}
+}
+
+// EclipticToEquatorial converts ecliptic to equatorial coordinates of the same equinox
+func (f Frame) EclipticToEquatorial(p Ecliptic) Equatorial {
+	lamdaRad := deg2rad(p.Longitude)
+	betaRad := deg2rad(p.Latitude)
+	epsilonRad := deg2rad(f.Epsilon)
+	alpha := math.Atan2(math.Sin(lamdaRad)*math.Cos(epsilonRad)-math.Tan(betaRad)*math.Sin(epsilonRad), math.Cos(lamdaRad))
+	delta := math.Asin(math.Sin(betaRad)*math.Cos(epsilonRad) + math.Cos(betaRad)*math.Sin(epsilonRad)*math.Sin(lamdaRad))
+	return Equatorial{RightAscension: limitDegrees(rad2deg(alpha)), Declination: rad2deg(delta)}
+}
+
+// EquatorialToEcliptic converts equatorial to ecliptic coordinates of the same equinox
+func (f Frame) EquatorialToEcliptic(p Equatorial) Ecliptic {
+	alphaRad := deg2rad(p.RightAscension)
+	deltaRad := deg2rad(p.Declination)
+	epsilonRad := deg2rad(f.Epsilon)
+	lamda := math.Atan2(math.Sin(alphaRad)*math.Cos(epsilonRad)+math.Tan(deltaRad)*math.Sin(epsilonRad), math.Cos(alphaRad))
+	beta := math.Asin(math.Sin(deltaRad)*math.Cos(epsilonRad) - math.Cos(deltaRad)*math.Sin(epsilonRad)*math.Sin(alphaRad))
+	return Ecliptic{Longitude: limitDegrees(rad2deg(lamda)), Latitude: rad2deg(beta)}
+}
+
+// EquatorialToHourAngle converts the right ascension to the local hour angle
+func (f Frame) EquatorialToHourAngle(p Equatorial) HourAngle {
+	return HourAngle{HourAngle: limitDegrees(f.Nu + f.Longitude - p.RightAscension), Declination: p.Declination}
+}
+
+// HourAngleToEquatorial converts the local hour angle to the right ascension
+func (f Frame) HourAngleToEquatorial(p HourAngle) Equatorial {
+	return Equatorial{RightAscension: limitDegrees(f.Nu + f.Longitude - p.HourAngle), Declination: p.Declination}
+}
+
+// HourAngleToHorizontal converts hour angle and declination to azimuth and elevation
+func (f Frame) HourAngleToHorizontal(p HourAngle) Horizontal {
+	latRad := deg2rad(f.Latitude)
+	hRad := deg2rad(p.HourAngle)
+	deltaRad := deg2rad(p.Declination)
+	e := math.Asin(math.Sin(latRad)*math.Sin(deltaRad) + math.Cos(latRad)*math.Cos(deltaRad)*math.Cos(hRad))
+	// azimuth westward from south, turned to eastward from north
+	azimuthAstro := math.Atan2(math.Sin(hRad)*math.Cos(deltaRad), math.Cos(hRad)*math.Cos(deltaRad)*math.Sin(latRad)-math.Sin(deltaRad)*math.Cos(latRad))
+	return Horizontal{Azimuth: limitDegrees(rad2deg(azimuthAstro) + 180.0), Elevation: rad2deg(e)}
+}
+
+// HorizontalToHourAngle converts azimuth and elevation to hour angle and declination
+func (f Frame) HorizontalToHourAngle(p Horizontal) HourAngle {
+	latRad := deg2rad(f.Latitude)
+	azimuthAstroRad := deg2rad(p.Azimuth - 180.0)
+	eRad := deg2rad(p.Elevation)
+	delta := math.Asin(math.Sin(latRad)*math.Sin(eRad) - math.Cos(latRad)*math.Cos(eRad)*math.Cos(azimuthAstroRad))
+	h := math.Atan2(math.Sin(azimuthAstroRad)*math.Cos(eRad), math.Cos(azimuthAstroRad)*math.Cos(eRad)*math.Sin(latRad)+math.Sin(eRad)*math.Cos(latRad))
+	return HourAngle{HourAngle: limitDegrees(rad2deg(h)), Declination: rad2deg(delta)}
+}
+
+// EquatorialToHorizontal converts right ascension and declination to azimuth and elevation
+func (f Frame) EquatorialToHorizontal(p Equatorial) Horizontal {
+	return f.HourAngleToHorizontal(f.EquatorialToHourAngle(p))
+}
+
+// HorizontalToEquatorial converts azimuth and elevation to right ascension and declination
+func (f Frame) HorizontalToEquatorial(p Horizontal) Equatorial {
+	return f.HourAngleToEquatorial(f.HorizontalToHourAngle(p))
+}
+
+// EclipticToHorizontal converts ecliptic coordinates to azimuth and elevation
+func (f Frame) EclipticToHorizontal(p Ecliptic) Horizontal {
+	return f.EquatorialToHorizontal(f.EclipticToEquatorial(p))
+}
+
+// HorizontalToEcliptic converts azimuth and elevation to ecliptic coordinates
+func (f Frame) HorizontalToEcliptic(p Horizontal) Ecliptic {
+	return f.EquatorialToEcliptic(f.HorizontalToEquatorial(p))
+}
+
+func deg2rad(degrees float64) float64 {
+	return (math.Pi / 180.0) * degrees
+}
+
+func rad2deg(radians float64) float64 {
+	return (180.0 / math.Pi) * radians
+}
+
+func limitDegrees(degrees float64) float64 {
+	limited := math.Mod(degrees, 360.0)
+	if limited < 0 {
+		limited += 360.0
+	}
+	return limited
+}
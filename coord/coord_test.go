@@ -0,0 +1,68 @@
+package coord
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	spa "github.com/maltegrosse/go-spa"
+)
+
+// angleDiff returns the difference of two angles [degrees] in the range -180 to 180
+func angleDiff(a, b float64) float64 {
+	return math.Remainder(a-b, 360)
+}
+
+// Meeus, Astronomical Algorithms, example 13.a (Pollux)
+func TestEquatorialToEcliptic(t *testing.T) {
+	f := Frame{Epsilon: 23.4392911}
+	p := f.EquatorialToEcliptic(Equatorial{RightAscension: 116.328942, Declination: 28.026183})
+	if math.Abs(p.Longitude-113.215630) > 1e-6 || math.Abs(p.Latitude-6.684170) > 1e-6 {
+		t.Errorf("got %+v, want longitude 113.215630 and latitude 6.684170", p)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	f := Frame{Epsilon: 23.44, Nu: 123.4, Latitude: -33.9, Longitude: 18.4}
+	for lamda := 0.0; lamda < 360; lamda += 30 {
+		for beta := -80.0; beta <= 80; beta += 20 {
+			p := Ecliptic{Longitude: lamda, Latitude: beta}
+			q := f.EquatorialToEcliptic(f.EclipticToEquatorial(p))
+			if math.Abs(angleDiff(q.Longitude, p.Longitude)) > 1e-9 || math.Abs(q.Latitude-p.Latitude) > 1e-9 {
+				t.Errorf("ecliptic %+v: got %+v", p, q)
+			}
+			r := f.HorizontalToEcliptic(f.EclipticToHorizontal(p))
+			if math.Abs(angleDiff(r.Longitude, p.Longitude)) > 1e-9 || math.Abs(r.Latitude-p.Latitude) > 1e-9 {
+				t.Errorf("horizontal %+v: got %+v", p, r)
+			}
+		}
+	}
+}
+
+// the conversions of the sun position reproduce the SPA results of the NREL example
+func TestSpaSunPosition(t *testing.T) {
+	s, err := spa.NewSpa(time.Date(2003, 10, 17, 12, 30, 30, 0, time.FixedZone("", -7*3600)),
+		39.742476, -105.1786, 1830.14, 820, 11, 67, 0, 30, -10, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFrame(s)
+	const tolerance = 1e-9
+
+	equatorial := f.EclipticToEquatorial(Ecliptic{Longitude: s.GetLamda(), Latitude: s.GetBeta()})
+	if math.Abs(angleDiff(equatorial.RightAscension, s.GetAlpha())) > tolerance || math.Abs(equatorial.Declination-s.GetDelta()) > tolerance {
+		t.Errorf("equatorial: got %+v, want %v %v", equatorial, s.GetAlpha(), s.GetDelta())
+	}
+	hourAngle := f.EquatorialToHourAngle(equatorial)
+	if math.Abs(angleDiff(hourAngle.HourAngle, s.GetH())) > tolerance {
+		t.Errorf("hour angle: got %v, want %v", hourAngle.HourAngle, s.GetH())
+	}
+	// the SPA elevation and azimuth follow from the topocentric hour angle and declination
+	horizontal := f.HourAngleToHorizontal(HourAngle{HourAngle: s.GetHPrime(), Declination: s.GetDeltaPrime()})
+	if math.Abs(horizontal.Elevation-s.GetE0()) > tolerance || math.Abs(angleDiff(horizontal.Azimuth, s.GetAzimuth())) > tolerance {
+		t.Errorf("horizontal: got %+v, want %v %v", horizontal, s.GetAzimuth(), s.GetE0())
+	}
+}
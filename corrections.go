@@ -0,0 +1,58 @@
+package spa
+
+import "math"
+
+// SpeedOfLight is the speed of light in vacuum [km/s]
+const SpeedOfLight = 299792.458
+
+// EarthRotationRate is the angular velocity of the earth rotation [radians/s]
+const EarthRotationRate = 7.292115e-5
+
+// topocentricCorrections applies the polar motion and the diurnal aberration to the topocentric sun declination
+// and local hour angle, and reports the change of the elevation and azimuth angle of each correction
+func (s *spa) topocentricCorrections() {
+	s.polarMotionE, s.polarMotionAzimuth = 0, 0
+	s.diurnalAberrationE, s.diurnalAberrationAzimuth = 0, 0
+	polarMotion := s.xp != 0 || s.yp != 0
+	if !polarMotion && !s.diurnalAberration {
+		return
+	}
+	lonRad := s.deg2rad(s.longitude)
+	position := func() (float64, float64) {
+		e := s.topocentricElevationAngle(s.latitude, s.deltaPrime, s.hPrime)
+		return e, s.topocentricAzimuthAngleAstro(s.hPrime, s.latitude, s.deltaPrime)
+	}
+	// topocentric sun direction in the earth-fixed frame of the hour angle
+	direction := unitVector(lonRad-s.deg2rad(s.hPrime), s.deg2rad(s.deltaPrime))
+	setDirection := func() {
+		s.deltaPrime = s.rad2deg(math.Asin(math.Max(-1, math.Min(1, direction[2]))))
+		s.hPrime = s.limitDegrees180pm(s.longitude - s.rad2deg(math.Atan2(direction[1], direction[0])))
+		s.alphaPrime = s.limitDegrees(s.nu + s.longitude - s.hPrime)
+	}
+
+	e, azimuth := position()
+	if polarMotion {
+		// terrestrial intermediate to international terrestrial reference system (IERS Conventions 2003)
+		arcsec := math.Pi / (180.0 * 3600.0)
+		direction = rotationX(-s.yp * arcsec).multiply(rotationY(-s.xp * arcsec)).apply(direction)
+		setDirection()
+		next, nextAzimuth := position()
+		s.polarMotionE, s.polarMotionAzimuth = next-e, s.limitDegrees180pm(nextAzimuth-azimuth)
+		e, azimuth = next, nextAzimuth
+	}
+	if s.diurnalAberration {
+		// the observer moves eastward with the earth rotation
+		radius, _ := ellipsoidAxes(s.ellipsoid)
+		rhoCos, _ := geocentricPosition(s.ellipsoid, s.deg2rad(s.latitude), s.elevation)
+		speed := EarthRotationRate * rhoCos * radius / 1000.0 / SpeedOfLight
+		direction[0] -= speed * math.Sin(lonRad)
+		direction[1] += speed * math.Cos(lonRad)
+		length := math.Sqrt(direction[0]*direction[0] + direction[1]*direction[1] + direction[2]*direction[2])
+		for i := range direction {
+			direction[i] /= length
+		}
+		setDirection()
+		next, nextAzimuth := position()
+		s.diurnalAberrationE, s.diurnalAberrationAzimuth = next-e, s.limitDegrees180pm(nextAzimuth-azimuth)
+	}
+}
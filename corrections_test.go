@@ -0,0 +1,58 @@
+package spa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTopocentricCorrections(t *testing.T) {
+	const arcSecond = 1.0 / 3600
+	baseline := newTestSpa(t)
+
+	// zero inputs leave the SPA output unchanged
+	s := newTestSpa(t)
+	s.SetPolarMotion(0, 0)
+	s.SetDiurnalAberration(false)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.GetE() != baseline.GetE() || s.GetAzimuth() != baseline.GetAzimuth() || s.GetIncidence() != baseline.GetIncidence() {
+		t.Errorf("zero corrections: got %v %v, want %v %v", s.GetE(), s.GetAzimuth(), baseline.GetE(), baseline.GetAzimuth())
+	}
+	if e, azimuth := s.GetPolarMotionEffect(); e != 0 || azimuth != 0 {
+		t.Errorf("zero polar motion effect: got %v %v", e, azimuth)
+	}
+	if e, azimuth := s.GetDiurnalAberrationEffect(); e != 0 || azimuth != 0 {
+		t.Errorf("zero diurnal aberration effect: got %v %v", e, azimuth)
+	}
+
+	// the pole offset of 0.5 arc seconds moves the sun by at most 0.5 arc seconds
+	s.SetPolarMotion(0.3, 0.4)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	e, azimuth := s.GetPolarMotionEffect()
+	shift := math.Hypot(e, azimuth*math.Cos(s.GetE0()*math.Pi/180))
+	if shift <= 0 || shift > 0.5*arcSecond*(1+1e-6) {
+		t.Errorf("polar motion: got a shift of %v arc seconds, want up to 0.5", shift/arcSecond)
+	}
+	if math.Abs(s.GetE0()-baseline.GetE0()-e) > 1e-12 {
+		t.Errorf("polar motion: elevation changed by %v, effect %v", s.GetE0()-baseline.GetE0(), e)
+	}
+
+	// the observer moves with at most 465 m/s, an aberration of 0.32 arc seconds times cos(latitude)
+	s.SetPolarMotion(0, 0)
+	s.SetDiurnalAberration(true)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	e, azimuth = s.GetDiurnalAberrationEffect()
+	shift = math.Hypot(e, azimuth*math.Cos(s.GetE0()*math.Pi/180))
+	limit := 0.3199 * math.Cos(s.GetLatitude()*math.Pi/180) * arcSecond
+	if shift < 0.1*limit || shift > limit {
+		t.Errorf("diurnal aberration: got a shift of %v arc seconds, want up to %v", shift/arcSecond, limit/arcSecond)
+	}
+	if math.Abs(s.GetE0()-baseline.GetE0()-e) > 1e-12 {
+		t.Errorf("diurnal aberration: elevation changed by %v, effect %v", s.GetE0()-baseline.GetE0(), e)
+	}
+}
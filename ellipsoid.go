@@ -0,0 +1,90 @@
+package spa
+
+import "math"
+
+// ellipsoidAxes returns the equatorial radius [meters] and the ratio of the polar to the equatorial radius
+func ellipsoidAxes(e Ellipsoids) (radius float64, ratio float64) {
+	switch e {
+	case EllipsoidWGS84:
+		return 6378137.0, 1 - 1/298.257223563
+	case EllipsoidGRS80:
+		return 6378137.0, 1 - 1/298.257222101
+	case EllipsoidIERS:
+		return 6378136.6, 1 - 1/298.25642
+	default:
+		return 6378140.0, 0.99664719
+	}
+}
+
+// GeodeticToECEF converts the geodetic latitude, longitude [degrees] and the height above the ellipsoid [meters]
+// to earth-centred earth-fixed coordinates [meters]
+func GeodeticToECEF(e Ellipsoids, latitude float64, longitude float64, height float64) (x float64, y float64, z float64) {
+	var s spa
+	radius, _ := ellipsoidAxes(e)
+	rhoCos, rhoSin := geocentricPosition(e, s.deg2rad(latitude), height)
+	lonRad := s.deg2rad(longitude)
+	return radius * rhoCos * math.Cos(lonRad), radius * rhoCos * math.Sin(lonRad), radius * rhoSin
+}
+
+// ECEFToGeodetic converts earth-centred earth-fixed coordinates [meters] to the geodetic latitude,
+// longitude [degrees] and the height above the ellipsoid [meters]
+func ECEFToGeodetic(e Ellipsoids, x float64, y float64, z float64) (latitude float64, longitude float64, height float64) {
+	var s spa
+	radius, ratio := ellipsoidAxes(e)
+	e2 := 1 - ratio*ratio
+	p := math.Hypot(x, y)
+
+	latRad := math.Atan2(z, p*(1-e2))
+	for i := 0; i < 10; i++ {
+		sin, cos := math.Sincos(latRad)
+		n := radius / math.Sqrt(1-e2*sin*sin)
+		if math.Abs(cos) > math.Abs(sin) {
+			height = p/cos - n
+		} else {
+			height = z/sin - n*(1-e2)
+		}
+		next := math.Atan2(z, p*(1-e2*n/(n+height)))
+		if math.Abs(next-latRad) < 1e-14 {
+			latRad = next
+			break
+		}
+		latRad = next
+	}
+	sin, cos := math.Sincos(latRad)
+	n := radius / math.Sqrt(1-e2*sin*sin)
+	if math.Abs(cos) > math.Abs(sin) {
+		height = p/cos - n
+	} else {
+		height = z/sin - n*(1-e2)
+	}
+	return s.rad2deg(latRad), s.rad2deg(math.Atan2(y, x)), height
+}
+
+// geocentricPosition calculates the observer distance from the earth axis (rho cos(phi')) and from the
+// equatorial plane (rho sin(phi')) [equatorial radii] for the geodetic latitude [radians] and the height [meters]
+func geocentricPosition(e Ellipsoids, latRad float64, height float64) (rhoCos float64, rhoSin float64) {
+	radius, ratio := ellipsoidAxes(e)
+	u := math.Atan(ratio * math.Tan(latRad))
+	rhoSin = ratio*math.Sin(u) + height*math.Sin(latRad)/radius
+	rhoCos = math.Cos(u) + height*math.Cos(latRad)/radius
+	return rhoCos, rhoSin
+}
+
+// observerGeocentricLatitude calculates the geocentric latitude [degrees] and the distance from the earth center [meters]
+func (s *spa) observerGeocentricLatitude() (float64, float64) {
+	radius, _ := ellipsoidAxes(s.ellipsoid)
+	rhoCos, rhoSin := geocentricPosition(s.ellipsoid, s.deg2rad(s.latitude), s.elevation)
+	return s.rad2deg(math.Atan2(rhoSin, rhoCos)), radius * math.Hypot(rhoCos, rhoSin)
+}
+
+// earthLimbDepression calculates the depression of the earth limb below the horizontal [degrees], the ellipsoid
+// is approximated by a sphere with the radius of the ellipsoid below the observer
+func (s *spa) earthLimbDepression() float64 {
+	if s.elevation <= 0 {
+		return 0
+	}
+	radius, _ := ellipsoidAxes(s.ellipsoid)
+	rhoCos, rhoSin := geocentricPosition(s.ellipsoid, s.deg2rad(s.latitude), 0)
+	surface := radius * math.Hypot(rhoCos, rhoSin)
+	return s.rad2deg(math.Acos(surface / (surface + s.elevation)))
+}
@@ -0,0 +1,55 @@
+package spa
+
+import (
+	"math"
+	"testing"
+)
+
+// the iteration of the geodetic latitude and height is exact for the WGS84 ellipsoid
+func TestECEFRoundTrip(t *testing.T) {
+	if x, y, z := GeodeticToECEF(EllipsoidWGS84, 0, 0, 0); x != 6378137 || y != 0 || z != 0 {
+		t.Errorf("equator at Greenwich: got %v %v %v, want 6378137 0 0", x, y, z)
+	}
+	// polar radius b = a (1 - f)
+	if _, _, z := GeodeticToECEF(EllipsoidWGS84, 90, 0, 0); math.Abs(z-6356752.314245) > 1e-6 {
+		t.Errorf("north pole: got z %v, want 6356752.314245", z)
+	}
+	for _, latitude := range []float64{-90, -60, -39.742476, 0, 0.5, 45, 89.9, 90} {
+		for _, height := range []float64{-400, 0, 1830.14, 400000, 35786000} {
+			x, y, z := GeodeticToECEF(EllipsoidWGS84, latitude, -105.1786, height)
+			gotLatitude, gotLongitude, gotHeight := ECEFToGeodetic(EllipsoidWGS84, x, y, z)
+			if math.Abs(gotLatitude-latitude) > 1e-10 || (math.Abs(latitude) != 90 && math.Abs(gotLongitude+105.1786) > 1e-10) ||
+				math.Abs(gotHeight-height) > 1e-6 {
+				t.Errorf("%v, %v m: got %v, %v, %v m", latitude, height, gotLatitude, gotLongitude, gotHeight)
+			}
+		}
+	}
+}
+
+// the position given as earth-centred earth-fixed coordinates follows a later change of the ellipsoid
+func TestObserverECEF(t *testing.T) {
+	x, y, z := GeodeticToECEF(EllipsoidWGS84, 39.742476, -105.1786, 1830.14)
+	s := newTestSpa(t)
+	s.SetEllipsoid(EllipsoidWGS84)
+	s.SetObserverECEF(x, y, z)
+	if math.Abs(s.GetLatitude()-39.742476) > 1e-10 || math.Abs(s.GetElevation()-1830.14) > 1e-6 {
+		t.Errorf("WGS84: got %v, %v m", s.GetLatitude(), s.GetElevation())
+	}
+
+	s.SetEllipsoid(EllipsoidIAU1976)
+	latitude, longitude, elevation := ECEFToGeodetic(EllipsoidIAU1976, x, y, z)
+	if s.GetLatitude() != latitude || s.GetLongitude() != longitude || s.GetElevation() != elevation {
+		t.Errorf("IAU 1976: got %v, %v, %v m, want %v, %v, %v m", s.GetLatitude(), s.GetLongitude(), s.GetElevation(),
+			latitude, longitude, elevation)
+	}
+	if math.Abs(s.GetElevation()-1830.14) < 0.5 {
+		t.Errorf("IAU 1976: elevation %v m unchanged from WGS84", s.GetElevation())
+	}
+
+	// a geodetic position replaces the earth-centred earth-fixed coordinates
+	s.SetLatitude(10)
+	s.SetEllipsoid(EllipsoidWGS84)
+	if s.GetLatitude() != 10 {
+		t.Errorf("latitude: got %v, want 10", s.GetLatitude())
+	}
+}
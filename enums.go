@@ -11,3 +11,102 @@ const (
 	SpaZaRts SPAFunctions = 2 //calculate zenith, azimuth, and sun rise/transit/set values
 	SpaAll   SPAFunctions = 3 //calculate all SPA output values
 )
+
+// HorizonDips defines the horizon dip correction for an observer above the surrounding terrain or sea
+type HorizonDips uint32
+
+// enumeration for the horizon dip correction applied to rise, set and crossing times
+//go:generate stringer -type=HorizonDips
+const (
+	DipNone      HorizonDips = 0 //flat sea-level horizon
+	DipGeometric HorizonDips = 1 //geometric dip of the horizon
+	DipRefracted HorizonDips = 2 //dip of the horizon including terrestrial refraction
+)
+
+// Twilights defines the twilight types by the elevation of the sun center
+type Twilights uint32
+
+// enumeration for twilight types
+//go:generate stringer -type=Twilights
+const (
+	CivilTwilight        Twilights = 0 //sun center 6 degrees below the horizon
+	NauticalTwilight     Twilights = 1 //sun center 12 degrees below the horizon
+	AstronomicalTwilight Twilights = 2 //sun center 18 degrees below the horizon
+)
+
+// RiseSetDefinitions defines the point of the solar disk which marks sunrise and sunset
+type RiseSetDefinitions uint32
+
+// enumeration for sunrise and sunset definitions
+//go:generate stringer -type=RiseSetDefinitions
+const (
+	RiseSetNrel       RiseSetDefinitions = 0 //upper limb with the constant sun radius (SunRadius)
+	RiseSetUpperLimb  RiseSetDefinitions = 1 //upper limb with the sun radius from the earth radius vector
+	RiseSetCenter     RiseSetDefinitions = 2 //center of the solar disk
+	RiseSetLowerLimb  RiseSetDefinitions = 3 //lower limb with the sun radius from the earth radius vector
+	RiseSetCustomDisk RiseSetDefinitions = 4 //custom fraction of the sun radius from the earth radius vector
+)
+
+// Ellipsoids defines the reference ellipsoid of the observer coordinates
+type Ellipsoids uint32
+
+// enumeration for reference ellipsoids
+//go:generate stringer -type=Ellipsoids
+const (
+	EllipsoidIAU1976 Ellipsoids = 0 //IAU 1976 ellipsoid of the SPA (a = 6378140 m, b/a = 0.99664719)
+	EllipsoidWGS84   Ellipsoids = 1 //World Geodetic System 1984 (a = 6378137 m, 1/f = 298.257223563)
+	EllipsoidGRS80   Ellipsoids = 2 //Geodetic Reference System 1980 (a = 6378137 m, 1/f = 298.257222101)
+	EllipsoidIERS    Ellipsoids = 3 //IERS Conventions 2003 (a = 6378136.6 m, 1/f = 298.25642)
+)
+
+// ObserverModes defines the observer environment
+type ObserverModes uint32
+
+// enumeration for observer modes
+//go:generate stringer -type=ObserverModes
+const (
+	ObserverGround ObserverModes = 0 //observer within the atmosphere, with refraction and horizon dip
+	ObserverSpace  ObserverModes = 1 //observer above the atmosphere (aircraft, balloon, orbit), without refraction, horizon at the earth limb
+)
+
+// EquatorialFrames defines the equator and equinox of equatorial coordinates
+type EquatorialFrames uint32
+
+// enumeration for equatorial reference frames
+//go:generate stringer -type=EquatorialFrames
+const (
+	FrameTrueOfDate EquatorialFrames = 0 //true equator and equinox of the date (precession and nutation), the SPA alpha and delta
+	FrameMeanOfDate EquatorialFrames = 1 //mean equator and equinox of the date (precession)
+	FrameJ2000      EquatorialFrames = 2 //mean equator and equinox of J2000.0
+	FrameICRS       EquatorialFrames = 3 //International Celestial Reference System, J2000.0 with the frame bias
+)
+
+// NutationModels defines the nutation series
+type NutationModels uint32
+
+// enumeration for nutation models
+//go:generate stringer -type=NutationModels
+const (
+	NutationSpa      NutationModels = 0 //63 term series of the SPA (IAU 1980 based)
+	NutationIAU2000B NutationModels = 1 //IAU 2000B series with 77 luni-solar terms
+)
+
+// JPLBodies defines the bodies of a JPL Development Ephemeris
+type JPLBodies uint32
+
+// enumeration for JPL ephemeris bodies, in the order of the file index
+//go:generate stringer -type=JPLBodies
+const (
+	JPLMercury             JPLBodies = 0  //Mercury
+	JPLVenus               JPLBodies = 1  //Venus
+	JPLEarthMoonBarycenter JPLBodies = 2  //barycenter of the earth and the moon
+	JPLMars                JPLBodies = 3  //Mars (system barycenter)
+	JPLJupiter             JPLBodies = 4  //Jupiter (system barycenter)
+	JPLSaturn              JPLBodies = 5  //Saturn (system barycenter)
+	JPLUranus              JPLBodies = 6  //Uranus (system barycenter)
+	JPLNeptune             JPLBodies = 7  //Neptune (system barycenter)
+	JPLPluto               JPLBodies = 8  //Pluto (system barycenter)
+	JPLMoon                JPLBodies = 9  //moon
+	JPLSun                 JPLBodies = 10 //sun
+	JPLEarth               JPLBodies = 11 //earth, derived from the earth-moon barycenter and the moon
+)
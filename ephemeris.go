@@ -0,0 +1,178 @@
+package spa
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EarthEphemeris calculates the heliocentric position of the earth
+type EarthEphemeris interface {
+	// EarthPosition returns the heliocentric longitude and latitude [degrees], referred to the ecliptic and
+	// equinox of the date, and the radius vector [Astronomical Units, AU] at the julian ephemeris millennium jme
+	EarthPosition(jme float64) (l float64, b float64, r float64)
+}
+
+// SpaEphemeris is the truncated VSOP87 series of the SPA
+type SpaEphemeris struct{}
+
+// VSOP87 holds the periodic terms of a body from a VSOP87 version D data file (heliocentric spherical
+// coordinates referred to the ecliptic and equinox of the date), like VSOP87D.ear for the earth
+type VSOP87 struct {
+	body      string
+	terms     [3][][][]float64 // coordinate (L, B, R), power of time, term (A, B, C)
+	counts    [3][]int         // terms of each series above the precision
+	precision float64
+}
+
+func (SpaEphemeris) EarthPosition(jme float64) (float64, float64, float64) {
+	var s spa
+	return s.earthHeliocentricLongitude(jme), s.earthHeliocentricLatitude(jme), s.earthRadiusVector(jme)
+}
+
+// LoadVSOP87 reads a VSOP87 version D data file of the original distribution. Each series starts with a header
+// line naming the version, the body, the variable and the power of time, the last three fields of a term
+// line are the amplitude A, the phase B and the frequency C.
+func LoadVSOP87(r io.Reader) (*VSOP87, error) {
+	var v VSOP87
+	coordinate, power := -1, -1
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "VSOP87" {
+			var err error
+			if coordinate, power, err = v.parseHeader(fields); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if coordinate < 0 {
+			return nil, errors.New("VSOP87 term before the series header")
+		}
+		if len(fields) < 3 {
+			return nil, errors.New("invalid VSOP87 term line")
+		}
+		term := make([]float64, 3)
+		for i := range term {
+			value, err := strconv.ParseFloat(fields[len(fields)-3+i], 64)
+			if err != nil {
+				return nil, errors.New("invalid VSOP87 term line")
+			}
+			term[i] = value
+		}
+		v.terms[coordinate][power] = append(v.terms[coordinate][power], term)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for coordinate := range v.terms {
+		if len(v.terms[coordinate]) == 0 {
+			return nil, errors.New("incomplete VSOP87 data, the L, B and R series are required")
+		}
+	}
+	v.SetPrecision(0)
+	return &v, nil
+}
+
+// parseHeader reads the coordinate and the power of time of a series header, like
+// "VSOP87 VERSION D4 EARTH VARIABLE 1 (LBR) *T**0 1080 TERMS ..."
+func (v *VSOP87) parseHeader(fields []string) (int, int, error) {
+	if len(fields) < 8 || fields[1] != "VERSION" || fields[4] != "VARIABLE" || !strings.HasPrefix(fields[7], "*T**") {
+		return 0, 0, errors.New("invalid VSOP87 series header")
+	}
+	if !strings.HasPrefix(fields[2], "D") {
+		return 0, 0, errors.New("unsupported VSOP87 version, version D is required")
+	}
+	if v.body == "" {
+		v.body = fields[3]
+	} else if v.body != fields[3] {
+		return 0, 0, errors.New("VSOP87 data of different bodies")
+	}
+	variable, err := strconv.Atoi(fields[5])
+	if err != nil || variable < 1 || variable > 3 {
+		return 0, 0, errors.New("invalid VSOP87 variable")
+	}
+	power, err := strconv.Atoi(strings.TrimPrefix(fields[7], "*T**"))
+	if err != nil || power < 0 || power > 5 {
+		return 0, 0, errors.New("invalid VSOP87 power of time")
+	}
+	coordinate := variable - 1
+	for len(v.terms[coordinate]) <= power {
+		v.terms[coordinate] = append(v.terms[coordinate], nil)
+	}
+	return coordinate, power, nil
+}
+
+// GetBody returns the body name of the data file
+func (v *VSOP87) GetBody() string {
+	return v.body
+}
+
+// SetPrecision skips the terms with an amplitude below the precision [radians or AU], which trades the
+// accuracy for the speed. The terms of a series are ordered by decreasing amplitude, 0 uses all terms.
+func (v *VSOP87) SetPrecision(precision float64) {
+	v.precision = precision
+	for coordinate := range v.terms {
+		v.counts[coordinate] = make([]int, len(v.terms[coordinate]))
+		for power, series := range v.terms[coordinate] {
+			count := 0
+			for i, term := range series {
+				if math.Abs(term[TermA]) >= precision {
+					count = i + 1
+				}
+			}
+			v.counts[coordinate][power] = count
+		}
+	}
+}
+
+// GetPrecision returns the amplitude [radians or AU] below which terms are skipped
+func (v *VSOP87) GetPrecision() float64 {
+	return v.precision
+}
+
+// GetTermCount returns the number of evaluated terms
+func (v *VSOP87) GetTermCount() int {
+	count := 0
+	for coordinate := range v.counts {
+		for _, c := range v.counts[coordinate] {
+			count += c
+		}
+	}
+	return count
+}
+
+// Position calculates the heliocentric longitude and latitude [degrees], referred to the ecliptic and
+// equinox of the date, and the radius vector [AU] of the body at the julian ephemeris millennium jme
+func (v *VSOP87) Position(jme float64) (l float64, b float64, r float64) {
+	var s spa
+	l = s.limitDegrees(s.rad2deg(v.coordinate(0, jme)))
+	b = s.rad2deg(v.coordinate(1, jme))
+	r = v.coordinate(2, jme)
+	return l, b, r
+}
+
+// EarthPosition calculates the position of the earth, the position is NaN for the series of another body
+func (v *VSOP87) EarthPosition(jme float64) (float64, float64, float64) {
+	if v.body != "EARTH" {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	return v.Position(jme)
+}
+
+// coordinate evaluates the series of the coordinate with the earth term summation of the SPA
+func (v *VSOP87) coordinate(coordinate int, jme float64) float64 {
+	var s spa
+	series := v.terms[coordinate]
+	sum := make([]float64, len(series))
+	for power := range series {
+		sum[power] = s.earthPeriodicTermSummation(series[power], v.counts[coordinate][power], jme)
+	}
+	return s.earthValues(sum, int64(len(series)), jme)
+}
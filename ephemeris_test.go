@@ -0,0 +1,97 @@
+package spa
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// formatVSOP87 writes the series [variable][power][term]{A [1e-8], B, C} in the layout of the VSOP87 data files
+func formatVSOP87(version string, body string, series [][][][]float64) string {
+	var b strings.Builder
+	for variable, powers := range series {
+		for power, terms := range powers {
+			fmt.Fprintf(&b, " VSOP87 VERSION %s    %-9s VARIABLE %d (LBR)       *T**%d  %5d TERMS    HELIOCENTRIC DYNAMICAL ECLIPTIC AND EQUINOX OF THE DATE\n",
+				version, body, variable+1, power, len(terms))
+			for i, term := range terms {
+				fmt.Fprintf(&b, " 4%c%d%d%5d  0  0  0  0  0  0  0  0  0  0  0  0     0.00000000000     0.00000000000%18.11f%14.11f%20.11f\n",
+					version[1], variable+1, power, i+1, term[TermA]/1e8, term[TermB], term[TermC])
+			}
+		}
+	}
+	return b.String()
+}
+
+// earthSeries returns the truncated earth series of the SPA
+func earthSeries() [][][][]float64 {
+	series := [][][][]float64{nil, nil, nil}
+	for variable, tables := range [][][][]float64{LTerms, BTerms, RTerms} {
+		counts := [][]int64{lSubcount, bSubcount, rSubcount}[variable]
+		for power, count := range counts {
+			series[variable] = append(series[variable], tables[power][:count])
+		}
+	}
+	return series
+}
+
+func TestLoadVSOP87(t *testing.T) {
+	earth, err := LoadVSOP87(strings.NewReader(formatVSOP87("D3", "EARTH", earthSeries())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if earth.GetBody() != "EARTH" || earth.GetTermCount() != 195 {
+		t.Errorf("got %v with %v terms, want EARTH with 195 terms", earth.GetBody(), earth.GetTermCount())
+	}
+
+	// the file of the SPA terms reproduces the built-in series
+	for _, jme := range []float64{-0.3, 0, 0.0037927819922933584, 0.25} {
+		l, b, r := earth.EarthPosition(jme)
+		wantL, wantB, wantR := SpaEphemeris{}.EarthPosition(jme)
+		if math.Abs(l-wantL) > 1e-10 || math.Abs(b-wantB) > 1e-10 || math.Abs(r-wantR) > 1e-12 {
+			t.Errorf("jme %v: got %v %v %v, want %v %v %v", jme, l, b, r, wantL, wantB, wantR)
+		}
+	}
+
+	// the precision drops the small terms
+	earth.SetPrecision(1e-5)
+	if count := earth.GetTermCount(); count >= 195 || count == 0 {
+		t.Errorf("precision 1e-5: got %v terms", count)
+	}
+	l, _, _ := earth.EarthPosition(0.0037927819922933584)
+	if wantL, _, _ := (SpaEphemeris{}).EarthPosition(0.0037927819922933584); math.Abs(l-wantL) > 0.01 {
+		t.Errorf("precision 1e-5: longitude %v, want %v", l, wantL)
+	}
+}
+
+func TestLoadVSOP87Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"version A", formatVSOP87("A3", "EARTH", earthSeries())},
+		{"missing variables", formatVSOP87("D3", "EARTH", earthSeries()[:2])},
+		{"term before header", "  4310    1  0  0  0  0  0  0  0  0  0  0  0  0     0.0     0.0  1.75347045673 0.00000000000      0.00000000000\n"},
+		{"invalid term", formatVSOP87("D3", "EARTH", earthSeries()) + " 4310 x y z\n"},
+	}
+	for _, test := range tests {
+		if _, err := LoadVSOP87(strings.NewReader(test.data)); err == nil {
+			t.Errorf("%v: no error", test.name)
+		}
+	}
+}
+
+func TestVSOP87EarthEphemerisBody(t *testing.T) {
+	mars, err := LoadVSOP87(strings.NewReader(formatVSOP87("D4", "MARS", earthSeries())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l, _, _ := mars.EarthPosition(0); !math.IsNaN(l) {
+		t.Errorf("earth position of the MARS series: got %v, want NaN", l)
+	}
+	s := newTestSpa(t)
+	s.SetEarthEphemeris(mars)
+	if err := s.Calculate(); err == nil {
+		t.Errorf("MARS series as the earth ephemeris: no error")
+	}
+}
@@ -0,0 +1,129 @@
+package spa
+
+import "math"
+
+// matrix3 is a 3x3 rotation matrix
+type matrix3 [3][3]float64
+
+// rotationX rotates the coordinate frame about the x axis by the angle [radians]
+func rotationX(angle float64) matrix3 {
+	sin, cos := math.Sincos(angle)
+	return matrix3{{1, 0, 0}, {0, cos, sin}, {0, -sin, cos}}
+}
+
+// rotationY rotates the coordinate frame about the y axis by the angle [radians]
+func rotationY(angle float64) matrix3 {
+	sin, cos := math.Sincos(angle)
+	return matrix3{{cos, 0, -sin}, {0, 1, 0}, {sin, 0, cos}}
+}
+
+// rotationZ rotates the coordinate frame about the z axis by the angle [radians]
+func rotationZ(angle float64) matrix3 {
+	sin, cos := math.Sincos(angle)
+	return matrix3{{cos, sin, 0}, {-sin, cos, 0}, {0, 0, 1}}
+}
+
+func (m matrix3) multiply(o matrix3) matrix3 {
+	var p matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				p[i][j] += m[i][k] * o[k][j]
+			}
+		}
+	}
+	return p
+}
+
+func (m matrix3) transpose() matrix3 {
+	var t matrix3
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			t[i][j] = m[j][i]
+		}
+	}
+	return t
+}
+
+func (m matrix3) apply(v [3]float64) [3]float64 {
+	var r [3]float64
+	for i := 0; i < 3; i++ {
+		r[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+	return r
+}
+
+// PrecessionMatrix rotates from the mean equator and equinox of J2000 to the mean equator and equinox of
+// the date, with the IAU 2006 precession angles (Capitaine et al., 2003) at the julian ephemeris century jce.
+// The transposed matrix rotates back to J2000.
+func PrecessionMatrix(jce float64) [3][3]float64 {
+	return precessionMatrix(jce)
+}
+
+// NutationMatrix rotates from the mean equator and equinox of the date to the true equator and equinox of the
+// date, with the mean obliquity epsilon0, the nutation longitude delPsi and the nutation obliquity delEpsilon
+// [degrees]. The transposed matrix rotates back to the mean equator and equinox.
+func NutationMatrix(epsilon0 float64, delPsi float64, delEpsilon float64) [3][3]float64 {
+	var s spa
+	return nutationMatrix(s.deg2rad(epsilon0), s.deg2rad(epsilon0+delEpsilon), s.deg2rad(delPsi))
+}
+
+// FrameBiasMatrix rotates from the ICRS to the mean equator and equinox of J2000 (IERS Conventions 2003).
+// The transposed matrix rotates back to the ICRS.
+func FrameBiasMatrix() [3][3]float64 {
+	return frameBiasMatrix()
+}
+
+func precessionMatrix(jce float64) matrix3 {
+	arcsec := math.Pi / (180.0 * 3600.0)
+	zeta := (2.650545 + jce*(2306.083227+jce*(0.2988499+jce*(0.01801828+jce*(-0.000005971+jce*-0.0000003173))))) * arcsec
+	z := (-2.650545 + jce*(2306.077181+jce*(1.0927348+jce*(0.01826837+jce*(-0.000028596+jce*-0.0000002904))))) * arcsec
+	theta := jce * (2004.191903 + jce*(-0.4294934+jce*(-0.04182264+jce*(-0.000007089+jce*-0.0000001274)))) * arcsec
+	return rotationZ(-z).multiply(rotationY(theta)).multiply(rotationZ(-zeta))
+}
+
+// nutationMatrix rotates from the mean equator and equinox of the date to the true equator and equinox
+// of the date, with the mean obliquity epsilon0, the true obliquity epsilon and the nutation longitude delPsi [radians]
+func nutationMatrix(epsilon0 float64, epsilon float64, delPsi float64) matrix3 {
+	return rotationX(-epsilon).multiply(rotationZ(-delPsi)).multiply(rotationX(epsilon0))
+}
+
+// frameBiasMatrix rotates from the ICRS to the mean equator and equinox of J2000
+func frameBiasMatrix() matrix3 {
+	mas := math.Pi / (180.0 * 3600.0 * 1000.0)
+	return rotationX(6.8192 * mas).multiply(rotationY(-16.617 * mas)).multiply(rotationZ(-14.6 * mas))
+}
+
+// frameMatrix rotates from the equatorial frame to the true equator and equinox of the date
+func (s *spa) frameMatrix(frame EquatorialFrames) matrix3 {
+	toDate := matrix3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	if frame == FrameTrueOfDate {
+		return toDate
+	}
+	toDate = toDate.multiply(nutationMatrix(s.deg2rad(s.epsilon0/3600.0), s.deg2rad(s.epsilon), s.deg2rad(s.delPsi)))
+	if frame == FrameMeanOfDate {
+		return toDate
+	}
+	toDate = toDate.multiply(precessionMatrix(s.jce))
+	if frame == FrameJ2000 {
+		return toDate
+	}
+	return toDate.multiply(frameBiasMatrix())
+}
+
+// convertEquatorial converts the right ascension and declination [degrees] between the equatorial frames
+// at the julian ephemeris century and with the nutation of the instance
+func (s *spa) convertEquatorial(alpha float64, delta float64, from EquatorialFrames, to EquatorialFrames) (float64, float64) {
+	v := s.frameMatrix(from).apply(unitVector(s.deg2rad(alpha), s.deg2rad(delta)))
+	v = s.frameMatrix(to).transpose().apply(v)
+	return s.limitDegrees(s.rad2deg(math.Atan2(v[1], v[0]))), s.rad2deg(math.Asin(math.Max(-1, math.Min(1, v[2]))))
+}
+
+// unitVector converts spherical angles [radians] to a cartesian unit vector
+func unitVector(longitude float64, latitude float64) [3]float64 {
+	return [3]float64{
+		math.Cos(latitude) * math.Cos(longitude),
+		math.Cos(latitude) * math.Sin(longitude),
+		math.Sin(latitude),
+	}
+}
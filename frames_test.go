@@ -0,0 +1,69 @@
+package spa
+
+import (
+	"math"
+	"testing"
+)
+
+// bias-precession matrix of the SOFA routine iauPmat06 at the TT date 2400000.5 + 50123.9999, from the
+// IAU 2006 Fukushima-Williams angles of iauPfw06 (gamma_bar, phi_bar, psi_bar including the frame bias)
+// and the mean obliquity of iauObl06
+func TestPrecessionMatrix(t *testing.T) {
+	want := [3][3]float64{
+		{0.9999995505176006994, 0.8695404617347167854e-3, 0.3779735201865137449e-3},
+		{-0.8695404723770991601e-3, 0.9999996219496026795, -0.1361752495765422566e-6},
+		{-0.3779734957033637540e-3, -0.1924880848602761318e-6, 0.9999999285679972427},
+	}
+	jce := (2400000.5 + 50123.9999 - 2451545.0) / 36525.0
+	got := matrix3(PrecessionMatrix(jce)).multiply(FrameBiasMatrix())
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(got[i][j]-want[i][j]) > 1e-11 {
+				t.Errorf("[%v][%v]: got %v, want %v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+// SOFA test case of iauNumat
+func TestNutationMatrix(t *testing.T) {
+	want := [3][3]float64{
+		{0.9999999999536227949, 0.8836238544090873336e-5, 0.3830835237722400669e-5},
+		{-0.8836082880798569274e-5, 0.9999999991354655028, -0.4063240865362499850e-4},
+		{-0.3831194272065995866e-5, 0.4063237480216291775e-4, 0.9999999991671660338},
+	}
+	degrees := 180.0 / math.Pi
+	got := NutationMatrix(0.4090789763356509900*degrees, -0.9630909107115582393e-5*degrees, 0.4063239174001678826e-4*degrees)
+	for i := range want {
+		for j := range want[i] {
+			if math.Abs(got[i][j]-want[i][j]) > 1e-11 {
+				t.Errorf("[%v][%v]: got %v, want %v", i, j, got[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestConvertEquatorial(t *testing.T) {
+	s := newTestSpa(t)
+	if alpha, delta := s.GetSunEquatorial(FrameTrueOfDate); alpha != s.GetAlpha() || delta != s.GetDelta() {
+		t.Errorf("true of date: got %v %v, want %v %v", alpha, delta, s.GetAlpha(), s.GetDelta())
+	}
+	// about 50 arc seconds of precession per year in longitude since J2000
+	alpha, delta := s.GetSunEquatorial(FrameJ2000)
+	if d := math.Hypot((alpha-s.GetAlpha())*math.Cos(s.GetDelta()*math.Pi/180), delta-s.GetDelta()); d < 0.03 || d > 0.06 {
+		t.Errorf("J2000: got %v %v, %v degrees from the true equator of the date", alpha, delta, d)
+	}
+
+	frames := []EquatorialFrames{FrameTrueOfDate, FrameMeanOfDate, FrameJ2000, FrameICRS}
+	for _, from := range frames {
+		for _, to := range frames {
+			for _, position := range [][2]float64{{0, 0}, {101.287, -16.716}, {279.235, 38.784}, {37.95, 89.26}} {
+				alpha, delta := s.ConvertEquatorial(position[0], position[1], from, to)
+				alpha, delta = s.ConvertEquatorial(alpha, delta, to, from)
+				if math.Abs(math.Remainder(alpha-position[0], 360)) > 1e-9 || math.Abs(delta-position[1]) > 1e-9 {
+					t.Errorf("%v to %v and back: got %v %v, want %v", from, to, alpha, delta, position)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package spa
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HorizonProfile describes the local horizon by elevation samples [degrees] over the
+// azimuth [degrees] (eastward from north). Elevations between the samples are linearly interpolated.
+type HorizonProfile struct {
+	azimuths   []float64
+	elevations []float64
+}
+
+// SunlightInterval defines a period of the day with the sun above the local horizon
+type SunlightInterval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewHorizonProfile creates a horizon profile from azimuth (eastward from north) and elevation samples [degrees]
+func NewHorizonProfile(azimuths []float64, elevations []float64) (*HorizonProfile, error) {
+	if len(azimuths) == 0 {
+		return nil, errors.New("empty horizon profile")
+	}
+	if len(azimuths) != len(elevations) {
+		return nil, errors.New("horizon profile azimuth and elevation count differ")
+	}
+	samples := make([][2]float64, len(azimuths))
+	for i := range azimuths {
+		if math.IsNaN(azimuths[i]) || math.IsInf(azimuths[i], 0) {
+			return nil, errors.New("invalid horizon profile azimuth")
+		}
+		if math.IsNaN(elevations[i]) || math.Abs(elevations[i]) > 90 {
+			return nil, errors.New("invalid horizon profile elevation")
+		}
+		azimuth := math.Mod(azimuths[i], 360)
+		if azimuth < 0 {
+			azimuth += 360
+		}
+		samples[i] = [2]float64{azimuth, elevations[i]}
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i][0] < samples[j][0] })
+
+	var p HorizonProfile
+	for i := range samples {
+		if i > 0 && samples[i][0] == samples[i-1][0] {
+			return nil, errors.New("duplicate horizon profile azimuth")
+		}
+		p.azimuths = append(p.azimuths, samples[i][0])
+		p.elevations = append(p.elevations, samples[i][1])
+	}
+	return &p, nil
+}
+
+// LoadHorizonProfileCSV reads a horizon profile with one "azimuth,elevation" pair [degrees] per line
+// (azimuth eastward from north). Comma, semicolon, tab and space separators are accepted, as well as
+// a header line and lines starting with '#'.
+func LoadHorizonProfileCSV(r io.Reader) (*HorizonProfile, error) {
+	var azimuths, elevations []float64
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		line++
+		azimuth, elevation, ok := parseHorizonSample(text)
+		if !ok {
+			if line == 1 {
+				// header
+				continue
+			}
+			return nil, errors.New("invalid horizon profile line: " + text)
+		}
+		azimuths = append(azimuths, azimuth)
+		elevations = append(elevations, elevation)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewHorizonProfile(azimuths, elevations)
+}
+
+// LoadHorizonProfilePVGIS reads the horizon export of the PVGIS tool (text or csv output). PVGIS
+// measures the azimuth from south (A: 0 = S, 90 = W, -90 = E), it is converted to eastward from north.
+func LoadHorizonProfilePVGIS(r io.Reader) (*HorizonProfile, error) {
+	var azimuths, elevations []float64
+	seen := make(map[float64]bool)
+	inTable := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		fields := splitHorizonFields(text)
+		if !inTable {
+			inTable = len(fields) >= 2 && fields[0] == "A" && fields[1] == "H_hor"
+			continue
+		}
+		azimuth, elevation, ok := parseHorizonSample(text)
+		if !ok {
+			if len(azimuths) > 0 {
+				// end of table, legend follows
+				break
+			}
+			continue
+		}
+		// the table starts and ends at north (-180 and 180)
+		azimuth = math.Mod(azimuth+540, 360)
+		if seen[azimuth] {
+			continue
+		}
+		seen[azimuth] = true
+		azimuths = append(azimuths, azimuth)
+		elevations = append(elevations, elevation)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !inTable {
+		return nil, errors.New("no PVGIS horizon table found")
+	}
+	return NewHorizonProfile(azimuths, elevations)
+}
+
+// GetElevation returns the interpolated horizon elevation [degrees] at the azimuth [degrees] (eastward from north)
+func (p *HorizonProfile) GetElevation(azimuth float64) float64 {
+	count := len(p.azimuths)
+	if count == 1 {
+		return p.elevations[0]
+	}
+	azimuth = math.Mod(azimuth, 360)
+	if azimuth < 0 {
+		azimuth += 360
+	}
+	i := sort.SearchFloat64s(p.azimuths, azimuth)
+	if i < count && p.azimuths[i] == azimuth {
+		return p.elevations[i]
+	}
+	// neighbouring samples, wrapping around north
+	lower, upper := i-1, i
+	lowerAzimuth, upperAzimuth := 0., 0.
+	if lower < 0 {
+		lower = count - 1
+		lowerAzimuth = p.azimuths[lower] - 360
+	} else {
+		lowerAzimuth = p.azimuths[lower]
+	}
+	if upper >= count {
+		upper = 0
+		upperAzimuth = p.azimuths[upper] + 360
+	} else {
+		upperAzimuth = p.azimuths[upper]
+	}
+	fraction := (azimuth - lowerAzimuth) / (upperAzimuth - lowerAzimuth)
+	return p.elevations[lower] + fraction*(p.elevations[upper]-p.elevations[lower])
+}
+
+// GetAzimuths returns the azimuth samples [degrees] (eastward from north)
+func (p *HorizonProfile) GetAzimuths() []float64 {
+	return append([]float64(nil), p.azimuths...)
+}
+
+// GetElevations returns the elevation samples [degrees] in the order of the azimuth samples
+func (p *HorizonProfile) GetElevations() []float64 {
+	return append([]float64(nil), p.elevations...)
+}
+
+func splitHorizonFields(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\t' || r == ' '
+	})
+}
+
+func parseHorizonSample(text string) (azimuth float64, elevation float64, ok bool) {
+	fields := splitHorizonFields(text)
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+	azimuth, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	elevation, err = strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return azimuth, elevation, true
+}
+
+// horizonElevation returns the elevation of the local horizon [degrees] at the azimuth [degrees],
+// which is the horizon profile or the flat horizon lowered by the horizon dip
+func (s *spa) horizonElevation(azimuth float64) float64 {
+	if s.horizonProfile != nil {
+		return s.horizonProfile.GetElevation(azimuth)
+	}
+	return -s.dip
+}
+
+// sunAboveHorizonAt calculates if the sun is above the local horizon at the second of the local day
+func (s *spa) sunAboveHorizonAt(daySecond int) (bool, error) {
+	sun := *s
+	sun.function = SpaZa
+	sun.hour = daySecond / 3600
+	sun.minute = daySecond % 3600 / 60
+	sun.second = float64(daySecond % 60)
+	if err := sun.Calculate(); err != nil {
+		return false, err
+	}
+	return sun.sunAboveHorizon, nil
+}
+
+// sunlight returns the sunlight intervals of the calculated day, which are calculated on the first request
+func (s *spa) sunlight() ([]SunlightInterval, error) {
+	if !s.sunlightCalculated {
+		intervals, err := s.calculateSunlightIntervals()
+		if err != nil {
+			return nil, err
+		}
+		s.sunlightIntervals, s.sunlightCalculated = intervals, true
+	}
+	return s.sunlightIntervals, nil
+}
+
+// calculateSunlightIntervals calculates the periods of the local day with the sun above the
+// local horizon, sampled every minute and refined to the second
+func (s *spa) calculateSunlightIntervals() ([]SunlightInterval, error) {
+	const step = 60
+	const daySeconds = 86400
+	var intervals []SunlightInterval
+
+	above, err := s.sunAboveHorizonAt(0)
+	if err != nil {
+		return nil, err
+	}
+	start := 0
+	for t := step; t <= daySeconds; t += step {
+		next, err := s.sunAboveHorizonAt(t)
+		if err != nil {
+			return nil, err
+		}
+		if next != above {
+			// bisect the change to the second
+			low, high := t-step, t
+			for high-low > 1 {
+				mid := (low + high) / 2
+				midAbove, err := s.sunAboveHorizonAt(mid)
+				if err != nil {
+					return nil, err
+				}
+				if midAbove == above {
+					low = mid
+				} else {
+					high = mid
+				}
+			}
+			if next {
+				start = high
+			} else {
+				intervals = append(intervals, SunlightInterval{Start: s.daySecondToDate(start), End: s.daySecondToDate(high)})
+			}
+			above = next
+		}
+	}
+	if above {
+		intervals = append(intervals, SunlightInterval{Start: s.daySecondToDate(start), End: s.daySecondToDate(daySeconds)})
+	}
+	return intervals, nil
+}
+
+func (s *spa) daySecondToDate(daySecond int) time.Time {
+	dt := time.Date(s.year, time.Month(s.month), s.day, 0, 0, 0, 0, time.FixedZone("ManualTimeZone", int(s.timezone*3600)))
+	return dt.Add(time.Second * time.Duration(daySecond))
+}
@@ -0,0 +1,200 @@
+package spa
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadHorizonProfileCSV(t *testing.T) {
+	data := `# horizon survey
+azimuth;elevation
+0;2
+90	6
+180, 1
+270 3.5
+`
+	p, err := LoadHorizonProfileCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		azimuth   float64
+		elevation float64
+	}{{0, 2}, {45, 4}, {90, 6}, {180, 1}, {315, 2.75}, {360, 2}, {-45, 2.75}}
+	for _, test := range tests {
+		if got := p.GetElevation(test.azimuth); math.Abs(got-test.elevation) > 1e-12 {
+			t.Errorf("azimuth %v: got %v, want %v", test.azimuth, got, test.elevation)
+		}
+	}
+
+	for _, data := range []string{"", "azimuth,elevation\n0,1\nx,2\n", "0,1\n360,2\n", "0,91\n"} {
+		if _, err := LoadHorizonProfileCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("%q: no error", data)
+		}
+	}
+}
+
+// layout of the PVGIS horizon text output, azimuth from south
+const testPVGISHorizon = `Latitude (decimal degrees):	45.812
+Longitude (decimal degrees):	8.628
+Horizon profile:
+
+A	H_hor	A_sun(w)	H_sun(w)	A_sun(s)	H_sun(s)
+-180.0	2.0	-180.0	0.0	-180.0	0.0
+-90.0	5.0	-90.0	0.0	-120.0	0.0
+0.0	1.0	0.0	21.2	0.0	67.6
+90.0	3.0	90.0	0.0	120.0	0.0
+180.0	2.0	180.0	0.0	180.0	0.0
+
+A: Azimuth (0 = S, 90 = W, -90 = E) (degree)
+H_hor: Horizon height (degree)
+`
+
+func TestLoadHorizonProfilePVGIS(t *testing.T) {
+	p, err := LoadHorizonProfilePVGIS(strings.NewReader(testPVGISHorizon))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if azimuths := p.GetAzimuths(); len(azimuths) != 4 {
+		t.Errorf("got azimuths %v, want north, east, south and west", azimuths)
+	}
+	tests := []struct {
+		azimuth   float64 // eastward from north
+		elevation float64
+	}{{0, 2}, {90, 5}, {135, 3}, {180, 1}, {270, 3}}
+	for _, test := range tests {
+		if got := p.GetElevation(test.azimuth); math.Abs(got-test.elevation) > 1e-12 {
+			t.Errorf("azimuth %v: got %v, want %v", test.azimuth, got, test.elevation)
+		}
+	}
+	if _, err := LoadHorizonProfilePVGIS(strings.NewReader("0,1\n90,2\n")); err == nil {
+		t.Errorf("missing PVGIS table: no error")
+	}
+}
+
+// a horizon of 10 degrees delays the sunrise to the apparent elevation of 10 degrees
+func TestSunlightIntervals(t *testing.T) {
+	s := newTestSpa(t)
+	p, err := NewHorizonProfile([]float64{0}, []float64{10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetHorizonProfile(p)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	intervals, err := s.GetSunlightIntervals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(intervals) != 1 {
+		t.Fatalf("got %v intervals, want 1", len(intervals))
+	}
+	rise, set, err := s.GetAltitudeCrossing(s.GetTrueElevation(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := intervals[0].Start.Sub(rise).Seconds(); math.Abs(d) > 60 {
+		t.Errorf("start: got %v, want %v", intervals[0].Start, rise)
+	}
+	if d := intervals[0].End.Sub(set).Seconds(); math.Abs(d) > 60 {
+		t.Errorf("end: got %v, want %v", intervals[0].End, set)
+	}
+}
+
+// the sun meets the horizon profile at the effective sunrise and sunset
+func TestEffectiveSunriseSunset(t *testing.T) {
+	p, err := NewHorizonProfile([]float64{0, 90, 180, 270}, []float64{0, 20, 0, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the intervals of the first day are not reused for the next day
+	s := newTestSpa(t)
+	s.SetHorizonProfile(p)
+	for _, date := range []time.Time{
+		time.Date(2003, 10, 17, 12, 0, 0, 0, time.FixedZone("", -7*3600)),
+		time.Date(2003, 10, 18, 12, 0, 0, 0, time.FixedZone("", -7*3600)),
+	} {
+		s.SetDate(date)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		rise, err := s.GetEffectiveSunrise()
+		if err != nil {
+			t.Fatal(err)
+		}
+		set, err := s.GetEffectiveSunset()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rise.Day() != date.Day() || set.Day() != date.Day() {
+			t.Errorf("%v: got sunrise %v and sunset %v of another day", date, rise, set)
+		}
+		for _, event := range []time.Time{rise, set} {
+			sun := newTestSpa(t)
+			sun.SetSPAFunction(SpaZa)
+			sun.SetDate(event)
+			if err := sun.Calculate(); err != nil {
+				t.Fatal(err)
+			}
+			// the sun moves by less than 0.01 degrees per second
+			if d := sun.GetE() - p.GetElevation(sun.GetAzimuth()); math.Abs(d) > 0.01 {
+				t.Errorf("%v: sun %v degrees from the horizon at %v", date, d, event)
+			}
+		}
+		// the profile raises the eastern horizon above the flat horizon of the SPA sunrise
+		if !rise.After(s.GetSunrise().Add(30 * time.Minute)) {
+			t.Errorf("%v: effective sunrise %v, SPA sunrise %v", date, rise, s.GetSunrise())
+		}
+	}
+}
+
+// the visible fraction of the solar disk goes from 0 below to 1 above the local horizon
+func TestDiskVisibleFraction(t *testing.T) {
+	want := newTestSpa(t)
+	if err := want.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if want.GetDiskVisibleFraction() != 1 {
+		t.Errorf("flat horizon: got %v, want 1", want.GetDiskVisibleFraction())
+	}
+	e := want.GetE()
+	for _, test := range []struct {
+		horizon  float64
+		fraction float64
+	}{{e - 1, 1}, {e - 0.3, 1}, {e, 0.5}, {e + 0.3, 0}, {e + 1, 0}} {
+		s := newTestSpa(t)
+		p, err := NewHorizonProfile([]float64{0}, []float64{test.horizon})
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.SetHorizonProfile(p)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(s.GetDiskVisibleFraction()-test.fraction) > 0.01 {
+			t.Errorf("horizon at %v: got %v, want %v", test.horizon, s.GetDiskVisibleFraction(), test.fraction)
+		}
+	}
+
+	// the refraction flattens the disk at the flat horizon, the fraction still grows with the sun elevation
+	s := want.(*spa)
+	center := s.GetTrueElevation(0)
+	if fraction := s.sunDiskVisibleFraction(center, s.r, 0); math.Abs(fraction-0.5) > 0.05 {
+		t.Errorf("disk center on the horizon: got %v, want about 0.5", fraction)
+	}
+	previous := 0.0
+	for e0 := center - 1; e0 <= center+1; e0 += 0.01 {
+		fraction := s.sunDiskVisibleFraction(e0, s.r, 0)
+		if fraction < previous || fraction < 0 || fraction > 1 {
+			t.Errorf("sun at %v: got %v after %v", e0, fraction, previous)
+		}
+		previous = fraction
+	}
+	if previous != 1 || s.sunDiskVisibleFraction(center-1, s.r, 0) != 0 {
+		t.Errorf("got %v one degree above and %v one degree below, want 1 and 0",
+			previous, s.sunDiskVisibleFraction(center-1, s.r, 0))
+	}
+}
@@ -0,0 +1,281 @@
+package spa
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+)
+
+// JPLEphemeris reads the Chebyshev records of a JPL Development Ephemeris binary file (DE430, DE440 and others
+// of the same layout), like linux_p1550p2650.440 of the JPL distribution. The positions are evaluated in the
+// ICRF, as an EarthEphemeris the earth position is rotated to the ecliptic and mean equinox of the date.
+type JPLEphemeris struct {
+	reader    io.ReaderAt
+	closer    io.Closer
+	order     binary.ByteOrder
+	number    int                // DE number
+	start     float64            // first julian ephemeris day
+	end       float64            // last julian ephemeris day
+	span      float64            // days of a record
+	au        float64            // Astronomical Unit [km]
+	emrat     float64            // earth-moon mass ratio
+	index     [13][3]int         // coefficient offset, coefficient count and sub intervals of the bodies
+	ncoeff    int                // doubles of a record
+	constants map[string]float64 // header constants by name
+
+	mutex   sync.Mutex
+	record  []float64 // cached record
+	current int       // index of the cached record, -1 for none
+}
+
+// OpenJPLEphemeris opens a JPL Development Ephemeris binary file, Close releases the file
+func OpenJPLEphemeris(name string) (*JPLEphemeris, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	e, err := NewJPLEphemeris(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	e.closer = f
+	return e, nil
+}
+
+// NewJPLEphemeris reads the header of a JPL Development Ephemeris binary file, the records are read on demand
+func NewJPLEphemeris(r io.ReaderAt) (*JPLEphemeris, error) {
+	e := &JPLEphemeris{reader: r, current: -1}
+	header := make([]byte, 2856)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, errors.New("invalid JPL ephemeris header")
+	}
+
+	// the byte order is taken from a plausible DE number
+	e.order = binary.LittleEndian
+	if numDE := e.order.Uint32(header[2840:]); numDE == 0 || numDE > 10000 {
+		e.order = binary.BigEndian
+	}
+	e.number = int(e.order.Uint32(header[2840:]))
+	if e.number == 0 || e.number > 10000 {
+		return nil, errors.New("invalid JPL ephemeris header")
+	}
+	e.start = math.Float64frombits(e.order.Uint64(header[2652:]))
+	e.end = math.Float64frombits(e.order.Uint64(header[2660:]))
+	e.span = math.Float64frombits(e.order.Uint64(header[2668:]))
+	ncon := int(e.order.Uint32(header[2676:]))
+	e.au = math.Float64frombits(e.order.Uint64(header[2680:]))
+	e.emrat = math.Float64frombits(e.order.Uint64(header[2688:]))
+	for i := 0; i < 12; i++ {
+		for j := 0; j < 3; j++ {
+			e.index[i][j] = int(e.order.Uint32(header[2696+(i*3+j)*4:]))
+		}
+	}
+	for j := 0; j < 3; j++ {
+		e.index[12][j] = int(e.order.Uint32(header[2844+j*4:]))
+	}
+	if e.span <= 0 || e.end <= e.start || e.au <= 0 || ncon < 0 || ncon > 10000 {
+		return nil, errors.New("invalid JPL ephemeris header")
+	}
+
+	// names of the constants beyond 400 follow the header, with the index of the lunar mantle
+	// and of TT-TDB behind them
+	names := make([]byte, ncon*6)
+	copy(names, header[252:2652])
+	extra := []int{}
+	if ncon > 400 {
+		more := make([]byte, (ncon-400)*6+24)
+		if _, err := r.ReadAt(more, 2856); err != nil {
+			return nil, errors.New("invalid JPL ephemeris header")
+		}
+		copy(names[2400:], more)
+		for j := 0; j < 6; j++ {
+			extra = append(extra, int(e.order.Uint32(more[(ncon-400)*6+j*4:])))
+		}
+	}
+
+	// record size from the last coefficient of the bodies, nutations have two components
+	// and TT-TDB one
+	components := func(i int) int {
+		if i == 11 {
+			return 2
+		}
+		return 3
+	}
+	for i, index := range e.index {
+		e.ncoeff = maxInt(e.ncoeff, index[0]-1+index[1]*index[2]*components(i))
+	}
+	for j := 0; j+2 < len(extra); j += 3 {
+		count := 3
+		if j == 3 {
+			count = 1
+		}
+		e.ncoeff = maxInt(e.ncoeff, extra[j]-1+extra[j+1]*extra[j+2]*count)
+	}
+	if e.ncoeff < 2 {
+		return nil, errors.New("invalid JPL ephemeris header")
+	}
+
+	values := make([]byte, ncon*8)
+	if _, err := r.ReadAt(values, int64(e.ncoeff*8)); err != nil {
+		return nil, errors.New("invalid JPL ephemeris constants")
+	}
+	e.constants = make(map[string]float64, ncon)
+	for i := 0; i < ncon; i++ {
+		name := strings.TrimSpace(string(names[i*6 : i*6+6]))
+		e.constants[name] = math.Float64frombits(e.order.Uint64(values[i*8:]))
+	}
+	return e, nil
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// Close releases the file of OpenJPLEphemeris
+func (e *JPLEphemeris) Close() error {
+	if e.closer == nil {
+		return nil
+	}
+	return e.closer.Close()
+}
+
+// GetNumber returns the DE number, like 440
+func (e *JPLEphemeris) GetNumber() int {
+	return e.number
+}
+
+// GetRange returns the first and the last julian ephemeris day of the file
+func (e *JPLEphemeris) GetRange() (float64, float64) {
+	return e.start, e.end
+}
+
+// GetConstant returns the header constant of the name, like AU or EMRAT
+func (e *JPLEphemeris) GetConstant(name string) (float64, bool) {
+	value, ok := e.constants[name]
+	return value, ok
+}
+
+// Position calculates the barycentric position [km] of the body in the ICRF at the julian ephemeris day jde (TDB)
+func (e *JPLEphemeris) Position(body JPLBodies, jde float64) ([3]float64, error) {
+	var position [3]float64
+	if body > JPLEarth {
+		return position, errors.New("invalid JPL ephemeris body")
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if body != JPLEarth && body != JPLMoon {
+		return e.interpolate(int(body), jde)
+	}
+
+	// the moon is stored geocentric, the earth follows from the earth-moon barycenter with the mass ratio
+	emb, err := e.interpolate(int(JPLEarthMoonBarycenter), jde)
+	if err != nil {
+		return position, err
+	}
+	moon, err := e.interpolate(int(JPLMoon), jde)
+	if err != nil {
+		return position, err
+	}
+	for i := range position {
+		position[i] = emb[i] - moon[i]/(1+e.emrat)
+		if body == JPLMoon {
+			position[i] += moon[i]
+		}
+	}
+	return position, nil
+}
+
+// interpolate evaluates the Chebyshev polynomials of the body index at the julian ephemeris day jde
+func (e *JPLEphemeris) interpolate(body int, jde float64) ([3]float64, error) {
+	var position [3]float64
+	if jde < e.start || jde > e.end {
+		return position, errors.New("date out of the JPL ephemeris range")
+	}
+	offset, count, intervals := e.index[body][0], e.index[body][1], e.index[body][2]
+	if count == 0 || intervals == 0 {
+		return position, errors.New("body not in the JPL ephemeris")
+	}
+	recordIndex := int((jde - e.start) / e.span)
+	if jde == e.end {
+		recordIndex--
+	}
+	if err := e.readRecord(recordIndex); err != nil {
+		return position, err
+	}
+
+	// sub interval of the record and the normalised time from -1 to 1 within it
+	length := e.span / float64(intervals)
+	interval := int((jde - e.record[0]) / length)
+	if interval >= intervals {
+		interval = intervals - 1
+	}
+	t := 2*(jde-e.record[0]-float64(interval)*length)/length - 1
+
+	polynomials := make([]float64, count)
+	polynomials[0] = 1
+	if count > 1 {
+		polynomials[1] = t
+	}
+	for i := 2; i < count; i++ {
+		polynomials[i] = 2*t*polynomials[i-1] - polynomials[i-2]
+	}
+	for component := range position {
+		coefficients := e.record[offset-1+(interval*3+component)*count:]
+		for i := count - 1; i >= 0; i-- {
+			position[component] += coefficients[i] * polynomials[i]
+		}
+	}
+	return position, nil
+}
+
+// readRecord reads the data record of the index into the cache, the records follow the header and the constants
+func (e *JPLEphemeris) readRecord(index int) error {
+	if index == e.current {
+		return nil
+	}
+	buffer := make([]byte, e.ncoeff*8)
+	if _, err := e.reader.ReadAt(buffer, int64(index+2)*int64(e.ncoeff*8)); err != nil {
+		return errors.New("missing JPL ephemeris record")
+	}
+	if e.record == nil {
+		e.record = make([]float64, e.ncoeff)
+	}
+	for i := range e.record {
+		e.record[i] = math.Float64frombits(e.order.Uint64(buffer[i*8:]))
+	}
+	e.current = index
+	return nil
+}
+
+// EarthPosition calculates the heliocentric earth position of the date from the ICRF positions of the earth and
+// the sun, rotated with the frame bias, the IAU 2006 precession and the mean obliquity of the SPA. Outside of the
+// range of the file the position is NaN.
+func (e *JPLEphemeris) EarthPosition(jme float64) (float64, float64, float64) {
+	var s spa
+	jde := 2451545.0 + jme*365250.0
+	earth, err := e.Position(JPLEarth, jde)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	sun, err := e.Position(JPLSun, jde)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	var v [3]float64
+	for i := range v {
+		v[i] = (earth[i] - sun[i]) / e.au
+	}
+	epsilon0 := s.deg2rad(s.eclipticMeanObliquity(jme) / 3600.0)
+	v = rotationX(epsilon0).multiply(precessionMatrix(jme * 10.0)).multiply(frameBiasMatrix()).apply(v)
+
+	r := math.Sqrt(v[0]*v[0] + v[1]*v[1] + v[2]*v[2])
+	return s.limitDegrees(s.rad2deg(math.Atan2(v[1], v[0]))), s.rad2deg(math.Asin(v[2] / r)), r
+}
@@ -0,0 +1,166 @@
+package spa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// synthetic DE file: two records of 32 days from 4 October 2003 with the earth of the SPA series
+const (
+	testJPLStart  = 2452916.5
+	testJPLSpan   = 32.0
+	testJPLCoeffs = 494
+	testJPLEmrat  = 81.3
+)
+
+var (
+	testJPLMoon = [3]float64{384400, 1000, -2000}
+	testJPLSun  = [3]float64{500000, -300000, 100000}
+)
+
+// testJPLEarth converts the heliocentric earth position of the SPA series to the ICRF [km]
+func testJPLEarth(jde float64) [3]float64 {
+	var s spa
+	jme := (jde - 2451545.0) / 365250.0
+	l, b, r := SpaEphemeris{}.EarthPosition(jme)
+	v := unitVector(s.deg2rad(l), s.deg2rad(b))
+	for i := range v {
+		v[i] *= r * AstronomicalUnit
+	}
+	epsilon0 := s.deg2rad(s.eclipticMeanObliquity(jme) / 3600.0)
+	return rotationX(epsilon0).multiply(precessionMatrix(jme * 10)).multiply(frameBiasMatrix()).transpose().apply(v)
+}
+
+// chebyshevFit fits n Chebyshev coefficients to the function on the interval from a to b
+func chebyshevFit(f func(float64) float64, a float64, b float64, n int) []float64 {
+	c := make([]float64, n)
+	for k := 0; k < n; k++ {
+		node := math.Pi * (float64(k) + 0.5) / float64(n)
+		fx := f(a + (math.Cos(node)+1)/2*(b-a))
+		for j := 0; j < n; j++ {
+			c[j] += 2.0 / float64(n) * fx * math.Cos(float64(j)*node)
+		}
+	}
+	c[0] /= 2
+	return c
+}
+
+// testJPLFile writes the header, the constants and the data records of the synthetic DE file. The earth-moon
+// barycenter has 14 coefficients in 4 sub intervals, the moon and the sun are constant, Pluto pads the records.
+func testJPLFile(order binary.ByteOrder) []byte {
+	record := func(values []float64) []byte {
+		b := make([]byte, testJPLCoeffs*8)
+		for i, v := range values {
+			order.PutUint64(b[i*8:], math.Float64bits(v))
+		}
+		return b
+	}
+	header := make([]byte, testJPLCoeffs*8)
+	copy(header, "SYNTHETIC TEST EPHEMERIS")
+	copy(header[252:], "AU    EMRAT ")
+	for i, v := range []float64{testJPLStart, testJPLStart + 2*testJPLSpan, testJPLSpan} {
+		order.PutUint64(header[2652+i*8:], math.Float64bits(v))
+	}
+	order.PutUint32(header[2676:], 2)
+	order.PutUint64(header[2680:], math.Float64bits(AstronomicalUnit))
+	order.PutUint64(header[2688:], math.Float64bits(testJPLEmrat))
+	index := map[JPLBodies][3]int{JPLEarthMoonBarycenter: {3, 14, 4}, JPLPluto: {183, 13, 8}, JPLMoon: {171, 2, 1}, JPLSun: {177, 2, 1}}
+	for body, entry := range index {
+		for j, v := range entry {
+			order.PutUint32(header[2696+(int(body)*3+j)*4:], uint32(v))
+		}
+	}
+	order.PutUint32(header[2840:], 440)
+
+	var file bytes.Buffer
+	file.Write(header)
+	file.Write(record([]float64{AstronomicalUnit, testJPLEmrat}))
+	for r := 0; r < 2; r++ {
+		values := make([]float64, testJPLCoeffs)
+		start := testJPLStart + float64(r)*testJPLSpan
+		values[0], values[1] = start, start+testJPLSpan
+		for interval := 0; interval < 4; interval++ {
+			a := start + float64(interval)*testJPLSpan/4
+			for component := 0; component < 3; component++ {
+				coefficients := chebyshevFit(func(jde float64) float64 {
+					return testJPLEarth(jde)[component] + testJPLSun[component] + testJPLMoon[component]/(1+testJPLEmrat)
+				}, a, a+testJPLSpan/4, 14)
+				copy(values[2+(interval*3+component)*14:], coefficients)
+			}
+		}
+		for component := 0; component < 3; component++ {
+			values[170+component*2] = testJPLMoon[component]
+			values[176+component*2] = testJPLSun[component]
+		}
+		file.Write(record(values))
+	}
+	return file.Bytes()
+}
+
+func TestJPLEphemeris(t *testing.T) {
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		e, err := NewJPLEphemeris(bytes.NewReader(testJPLFile(order)))
+		if err != nil {
+			t.Fatalf("%v: %v", order, err)
+		}
+		start, end := e.GetRange()
+		if e.GetNumber() != 440 || start != testJPLStart || end != testJPLStart+2*testJPLSpan {
+			t.Errorf("%v: got DE%v from %v to %v", order, e.GetNumber(), start, end)
+		}
+		if emrat, ok := e.GetConstant("EMRAT"); !ok || emrat != testJPLEmrat {
+			t.Errorf("%v: EMRAT got %v", order, emrat)
+		}
+
+		// the moon is geocentric in the file and barycentric in the result
+		jde := testJPLStart + 40.3
+		moon, err := e.Position(JPLMoon, jde)
+		if err != nil {
+			t.Fatal(err)
+		}
+		earth := testJPLEarth(jde)
+		for i := range moon {
+			if want := earth[i] + testJPLSun[i] + testJPLMoon[i]; math.Abs(moon[i]-want) > 1 {
+				t.Errorf("%v: moon component %v: got %v km, want %v km", order, i, moon[i], want)
+			}
+		}
+		if _, err := e.Position(JPLSun, testJPLStart-1); err == nil {
+			t.Errorf("%v: no error before the first record", order)
+		}
+	}
+}
+
+func TestJPLEphemerisSpa(t *testing.T) {
+	e, err := NewJPLEphemeris(bytes.NewReader(testJPLFile(binary.LittleEndian)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newTestSpa(t)
+	s.SetEarthEphemeris(e)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	// NREL SPA example values
+	if math.Abs(s.GetZenith()-50.111622) > 1e-6 || math.Abs(s.GetAzimuth()-194.340241) > 1e-6 {
+		t.Errorf("got zenith %v azimuth %v, want 50.111622 194.340241", s.GetZenith(), s.GetAzimuth())
+	}
+	if rise := s.GetSunrise().Format("15:04:05"); rise != "06:12:43" {
+		t.Errorf("sunrise: got %v, want 06:12:43", rise)
+	}
+
+	// the previous day of the rise/transit/set stage is out of the range
+	s.SetDate(time.Date(2003, 10, 4, 12, 0, 0, 0, time.UTC))
+	if err := s.Calculate(); err == nil {
+		t.Errorf("rise/transit/set at the start of the file: no error")
+	}
+	s.SetSPAFunction(SpaZa)
+	if err := s.Calculate(); err != nil {
+		t.Errorf("position at the start of the file: %v", err)
+	}
+	s.SetDate(time.Date(2003, 12, 8, 12, 0, 0, 0, time.UTC))
+	if err := s.Calculate(); err == nil {
+		t.Errorf("position after the end of the file: no error")
+	}
+}
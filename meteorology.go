@@ -0,0 +1,114 @@
+package spa
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// MeteoSample holds the weather observation of a timestamp
+type MeteoSample struct {
+	Time        time.Time
+	Pressure    float64 // local pressure [millibars]
+	Temperature float64 // local temperature [degrees Celsius]
+}
+
+// MeteoSeries describes the local pressure and temperature over time. Values between the samples are
+// linearly interpolated, before the first and after the last sample the nearest sample is used.
+type MeteoSeries struct {
+	samples []MeteoSample
+}
+
+// NewMeteoSeries creates a meteorology series from weather samples in any order
+func NewMeteoSeries(samples []MeteoSample) (*MeteoSeries, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("empty meteorology series")
+	}
+	var m MeteoSeries
+	m.samples = append([]MeteoSample(nil), samples...)
+	sort.Slice(m.samples, func(i, j int) bool { return m.samples[i].Time.Before(m.samples[j].Time) })
+	for i, sample := range m.samples {
+		if i > 0 && sample.Time.Equal(m.samples[i-1].Time) {
+			return nil, errors.New("duplicate meteorology sample time")
+		}
+		if math.IsNaN(sample.Pressure) || (sample.Pressure < 0) || (sample.Pressure > 5000) {
+			return nil, errors.New("invalid meteorology sample pressure")
+		}
+		if math.IsNaN(sample.Temperature) || (sample.Temperature <= -273) || (sample.Temperature > 6000) {
+			return nil, errors.New("invalid meteorology sample temperature")
+		}
+	}
+	return &m, nil
+}
+
+// At returns the interpolated pressure [millibars] and temperature [degrees Celsius] at the time
+func (m *MeteoSeries) At(t time.Time) (pressure float64, temperature float64) {
+	count := len(m.samples)
+	i := sort.Search(count, func(i int) bool { return !m.samples[i].Time.Before(t) })
+	if i == 0 {
+		return m.samples[0].Pressure, m.samples[0].Temperature
+	}
+	if i == count {
+		return m.samples[count-1].Pressure, m.samples[count-1].Temperature
+	}
+	lower, upper := m.samples[i-1], m.samples[i]
+	fraction := float64(t.Sub(lower.Time)) / float64(upper.Time.Sub(lower.Time))
+	pressure = lower.Pressure + fraction*(upper.Pressure-lower.Pressure)
+	temperature = lower.Temperature + fraction*(upper.Temperature-lower.Temperature)
+	return pressure, temperature
+}
+
+// GetSamples returns the weather samples in time order
+func (m *MeteoSeries) GetSamples() []MeteoSample {
+	return append([]MeteoSample(nil), m.samples...)
+}
+
+// applyMeteorology sets the weather of the calculation to the meteorology series at the time, or to the
+// pressure and temperature inputs without a series, derived from the elevation when they are not given.
+// The inputs are left unchanged.
+func (s *spa) applyMeteorology(t time.Time) {
+	s.localPressure, s.localTemperature = s.pressure, s.temperature
+	s.applyStandardAtmosphere()
+	if s.meteorology != nil {
+		s.localPressure, s.localTemperature = s.meteorology.At(t)
+	}
+}
+
+// meteorologyRiseSet recalculates sunrise and sunset with the refraction of the weather at the rise and set times
+func (s *spa) meteorologyRiseSet(rts riseTransitSet) riseTransitSet {
+	rts.hPrime = append([]float64(nil), rts.hPrime...)
+
+	sun := *s
+	sun.applyMeteorology(s.localHourToDate(rts.rise))
+	if rise, ok := sun.sunRiseTransitSet(sun.riseSetSunAltitude()); ok {
+		rts.rise = rise.rise
+		rts.riseAzimuth = rise.riseAzimuth
+		rts.hPrime[SunRise] = rise.hPrime[SunRise]
+	}
+
+	sun = *s
+	sun.applyMeteorology(s.localHourToDate(rts.set))
+	if set, ok := sun.sunRiseTransitSet(sun.riseSetSunAltitude()); ok {
+		rts.set = set.set
+		rts.setAzimuth = set.setAzimuth
+		rts.hPrime[SunSet] = set.hPrime[SunSet]
+	}
+
+	rts.length = 24.0 * s.limitZero2one((rts.set-rts.rise)/24.0)
+	return rts
+}
+
+// calculateSeries calculates a copy of the instance for each date
+func (s *spa) calculateSeries(dates []time.Time) ([]Spa, error) {
+	results := make([]Spa, len(dates))
+	for i, dt := range dates {
+		sun := *s
+		sun.SetDate(dt)
+		if err := sun.Calculate(); err != nil {
+			return nil, err
+		}
+		results[i] = &sun
+	}
+	return results, nil
+}
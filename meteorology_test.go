@@ -0,0 +1,96 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMeteoSeriesAt(t *testing.T) {
+	start := time.Date(2003, 10, 17, 0, 0, 0, 0, time.UTC)
+	series, err := NewMeteoSeries([]MeteoSample{
+		{Time: start.Add(12 * time.Hour), Pressure: 800, Temperature: 20},
+		{Time: start, Pressure: 820, Temperature: 10},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		time        time.Time
+		pressure    float64
+		temperature float64
+	}{
+		{start.Add(-time.Hour), 820, 10},
+		{start.Add(3 * time.Hour), 815, 12.5},
+		{start.Add(12 * time.Hour), 800, 20},
+		{start.Add(24 * time.Hour), 800, 20},
+	}
+	for _, test := range tests {
+		pressure, temperature := series.At(test.time)
+		if math.Abs(pressure-test.pressure) > 1e-9 || math.Abs(temperature-test.temperature) > 1e-9 {
+			t.Errorf("at %v: got %v mbar %v C, want %v mbar %v C", test.time, pressure, temperature, test.pressure, test.temperature)
+		}
+	}
+}
+
+func TestMeteorologyKeepsInputs(t *testing.T) {
+	s, err := NewSpa(time.Date(2003, 10, 17, 12, 30, 30, 0, time.FixedZone("", -7*3600)),
+		39.742476, -105.1786, 1830.14, 820, 11, 67, 0, 30, -10, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixed := s.GetE()
+
+	series, err := NewMeteoSeries([]MeteoSample{{Time: s.GetDate(), Pressure: 1010, Temperature: -20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetMeteorology(series)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.GetE() == fixed {
+		t.Errorf("the meteorology series does not change the refraction")
+	}
+	if s.GetPressure() != 820 || s.GetTemperature() != 11 {
+		t.Errorf("inputs overwritten: got %v mbar %v C, want 820 mbar 11 C", s.GetPressure(), s.GetTemperature())
+	}
+
+	s.SetMeteorology(nil)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if s.GetE() != fixed {
+		t.Errorf("elevation without the series: got %v, want %v", s.GetE(), fixed)
+	}
+}
+
+// the defaulted inputs are reported with a meteorology series, which sets the weather of the calculation
+func TestMeteorologyDefaultedWeather(t *testing.T) {
+	s := newTestSpa(t)
+	s.SetPressure(math.NaN())
+	s.SetTemperature(math.NaN())
+	series, err := NewMeteoSeries([]MeteoSample{{Time: s.GetDate(), Pressure: 1010, Temperature: -20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetMeteorology(series)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.GetPressureDefaulted() || !s.GetTemperatureDefaulted() {
+		t.Errorf("defaulted inputs not reported with a meteorology series")
+	}
+	if s.GetLocalPressure() != 1010 || s.GetLocalTemperature() != -20 {
+		t.Errorf("got %v mbar %v C, want 1010 mbar -20 C", s.GetLocalPressure(), s.GetLocalTemperature())
+	}
+
+	s.SetMeteorology(nil)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	pressure, temperature := StandardAtmosphere(s.GetElevation())
+	if s.GetLocalPressure() != pressure || s.GetLocalTemperature() != temperature {
+		t.Errorf("without the series: got %v mbar %v C, want %v mbar %v C", s.GetLocalPressure(), s.GetLocalTemperature(), pressure, temperature)
+	}
+}
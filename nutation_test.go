@@ -0,0 +1,57 @@
+package spa
+
+import (
+	"math"
+	"testing"
+)
+
+// SOFA test case of iauNut00b at the TT date 2400000.5 + 53736
+func TestNutationIAU2000B(t *testing.T) {
+	var s spa
+	s.nutationIAU2000B((2400000.5 + 53736.0 - 2451545.0) / 36525.0)
+	radians := math.Pi / 180.0
+	if got := s.delPsi * radians; math.Abs(got-(-0.9632552291148362783e-5)) > 1e-13 {
+		t.Errorf("delPsi: got %v rad, want -0.9632552291148362783e-5 rad", got)
+	}
+	if got := s.delEpsilon * radians; math.Abs(got-0.4063197106621159367e-4) > 1e-13 {
+		t.Errorf("delEpsilon: got %v rad, want 0.4063197106621159367e-4 rad", got)
+	}
+}
+
+// differences between the IAU 2000B and the SPA nutation quoted by nutationIAU2000B, sampled every 5 days
+func TestNutationIAU2000BAgainstSpa(t *testing.T) {
+	tests := []struct {
+		from, to float64 // years
+		psi, eps float64 // measured maximum differences [arc seconds]
+	}{
+		{1900, 2100, 0.02, 0.01},
+		{1000, 1001, 0.07, 0.03},
+		{3000, 3001, 0.07, 0.03},
+		{0, 1, 0.10, 0.14},
+		{4000, 4001, 0.23, 0.12},
+		{-2000, -1999, 0.32, 0.54},
+		{6000, 6001, 1.07, 0.35},
+	}
+	var s spa
+	x := make([]float64, TermXCount)
+	for _, test := range tests {
+		maxPsi, maxEpsilon := 0.0, 0.0
+		for year := test.from; year <= test.to; year += 5 / 365.25 {
+			jce := (year - 2000) / 100
+			x[TermX0] = s.meanElongationMoonSun(jce)
+			x[TermX1] = s.meanAnomalySun(jce)
+			x[TermX2] = s.meanAnomalyMoon(jce)
+			x[TermX3] = s.argumentLatitudeMoon(jce)
+			x[TermX4] = s.ascendingLongitudeMoon(jce)
+			s.nutationLongitudeAndObliquity(jce, x)
+			psi, epsilon := s.delPsi, s.delEpsilon
+			s.nutationIAU2000B(jce)
+			maxPsi = math.Max(maxPsi, math.Abs(psi-s.delPsi)*3600)
+			maxEpsilon = math.Max(maxEpsilon, math.Abs(epsilon-s.delEpsilon)*3600)
+		}
+		if maxPsi > test.psi || maxEpsilon > test.eps {
+			t.Errorf("years %v to %v: got %.3f and %.3f arc seconds, want below %v and %v",
+				test.from, test.to, maxPsi, maxEpsilon, test.psi, test.eps)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package orbit
+
+// ShadowModels defines the geometry of the earth shadow
+type ShadowModels uint32
+
+// enumeration for earth shadow models
+//go:generate stringer -type=ShadowModels
+const (
+	ShadowCylindrical ShadowModels = 0 //cylinder of the earth radius along the sun direction, without penumbra
+	ShadowConical     ShadowModels = 1 //umbra and penumbra cones of the sun and earth disks
+)
@@ -0,0 +1,209 @@
+// Package orbit calculates the solar beta angle and the earth shadow passages of a spacecraft.
+//
+// The orbit is given as Keplerian elements or as a state vector in the geocentric inertial frame of the
+// J2000 mean equator and equinox, and is propagated with the two-body motion and the secular J2 drift of
+// the node, perigee and mean anomaly. The sun is the geocentric sun vector of the SPA (spa.SunVectors),
+// the earth is a sphere of the WGS84 equatorial radius.
+package orbit
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// earth constants
+const (
+	EarthGM     = 398600.4418   // earth gravitational parameter [km^3/s^2]
+	EarthRadius = 6378.137      // earth equatorial radius [km]
+	EarthJ2     = 1.08262668e-3 // earth second zonal harmonic
+	SunRadius   = 696000.0      // sun radius [km]
+)
+
+// Elements holds the Keplerian elements of an earth orbit in the J2000 frame
+type Elements struct {
+	Epoch             time.Time
+	SemiMajorAxis     float64 // semi-major axis [km]
+	Eccentricity      float64 // eccentricity, valid range: 0 to <1
+	Inclination       float64 // inclination [degrees]
+	RAAN              float64 // right ascension of the ascending node [degrees]
+	ArgumentOfPerigee float64 // argument of perigee [degrees]
+	MeanAnomaly       float64 // mean anomaly at the epoch [degrees]
+}
+
+// StateVector holds the position and velocity of a spacecraft in the J2000 frame
+type StateVector struct {
+	Time     time.Time
+	Position [3]float64 // position [km]
+	Velocity [3]float64 // velocity [km/s]
+}
+
+// Validate checks the elements for a closed earth orbit
+func (e Elements) Validate() error {
+	if e.SemiMajorAxis <= 0 {
+		return errors.New("invalid semi-major axis")
+	}
+	if (e.Eccentricity < 0) || (e.Eccentricity >= 1) {
+		return errors.New("invalid eccentricity")
+	}
+	if (e.Inclination < 0) || (e.Inclination > 180) {
+		return errors.New("invalid inclination")
+	}
+	return nil
+}
+
+// ElementsFromStateVector converts a state vector to the Keplerian elements at the time of the state vector.
+// The argument of perigee of a circular orbit and the node of an equatorial orbit are set to zero.
+func ElementsFromStateVector(sv StateVector) (Elements, error) {
+	var e Elements
+	e.Epoch = sv.Time
+	r := sv.Position
+	v := sv.Velocity
+	rNorm := norm(r)
+	if rNorm == 0 {
+		return e, errors.New("invalid position")
+	}
+	h := cross(r, v)
+	hNorm := norm(h)
+	if hNorm == 0 {
+		return e, errors.New("radial trajectory")
+	}
+	energy := dot(v, v)/2 - EarthGM/rNorm
+	if energy >= 0 {
+		return e, errors.New("orbit is not closed")
+	}
+	e.SemiMajorAxis = -EarthGM / (2 * energy)
+
+	// eccentricity vector and node vector
+	vxh := cross(v, h)
+	var ecc [3]float64
+	for i := range ecc {
+		ecc[i] = vxh[i]/EarthGM - r[i]/rNorm
+	}
+	e.Eccentricity = norm(ecc)
+	node := [3]float64{-h[1], h[0], 0}
+	nodeNorm := norm(node)
+
+	e.Inclination = rad2deg(math.Acos(h[2] / hNorm))
+	if nodeNorm > 1e-12*hNorm {
+		e.RAAN = limitDegrees(rad2deg(math.Atan2(node[1], node[0])))
+	} else {
+		// equatorial orbit, the node is measured along the x axis
+		node = [3]float64{1, 0, 0}
+		nodeNorm = 1
+	}
+
+	// true anomaly from the perigee or, for a circular orbit, argument of latitude from the node
+	nodeUnit := [3]float64{node[0] / nodeNorm, node[1] / nodeNorm, 0}
+	inPlane := cross(h, nodeUnit)
+	for i := range inPlane {
+		inPlane[i] /= hNorm
+	}
+	latitude := math.Atan2(dot(r, inPlane), dot(r, nodeUnit))
+	nu := latitude
+	if e.Eccentricity > 1e-11 {
+		perigee := math.Atan2(dot(ecc, inPlane), dot(ecc, nodeUnit))
+		e.ArgumentOfPerigee = limitDegrees(rad2deg(perigee))
+		nu = latitude - perigee
+	}
+	eccentricAnomaly := 2 * math.Atan(math.Sqrt((1-e.Eccentricity)/(1+e.Eccentricity))*math.Tan(nu/2))
+	e.MeanAnomaly = limitDegrees(rad2deg(eccentricAnomaly - e.Eccentricity*math.Sin(eccentricAnomaly)))
+	return e, nil
+}
+
+// StateVector propagates the orbit to the time with the two-body motion and the secular J2 drift
+func (e Elements) StateVector(t time.Time) StateVector {
+	sv := StateVector{Time: t}
+	dt := t.Sub(e.Epoch).Seconds()
+	raan, perigee, meanAnomaly := e.secularElements(dt)
+
+	eccentricAnomaly := meanAnomaly
+	for i := 0; i < 50; i++ {
+		correction := (eccentricAnomaly - e.Eccentricity*math.Sin(eccentricAnomaly) - meanAnomaly) /
+			(1 - e.Eccentricity*math.Cos(eccentricAnomaly))
+		eccentricAnomaly -= correction
+		if math.Abs(correction) < 1e-14 {
+			break
+		}
+	}
+	sinE, cosE := math.Sincos(eccentricAnomaly)
+	root := math.Sqrt(1 - e.Eccentricity*e.Eccentricity)
+	radius := e.SemiMajorAxis * (1 - e.Eccentricity*cosE)
+	speed := math.Sqrt(EarthGM*e.SemiMajorAxis) / radius
+	position := [3]float64{e.SemiMajorAxis * (cosE - e.Eccentricity), e.SemiMajorAxis * root * sinE, 0}
+	velocity := [3]float64{-speed * sinE, speed * root * cosE, 0}
+
+	// perifocal to the J2000 frame
+	incRad := deg2rad(e.Inclination)
+	for _, rotate := range []func([3]float64) [3]float64{
+		func(v [3]float64) [3]float64 { return rotateZ(v, perigee) },
+		func(v [3]float64) [3]float64 { return rotateX(v, incRad) },
+		func(v [3]float64) [3]float64 { return rotateZ(v, raan) },
+	} {
+		position = rotate(position)
+		velocity = rotate(velocity)
+	}
+	sv.Position = position
+	sv.Velocity = velocity
+	return sv
+}
+
+// Normal returns the unit vector of the orbit normal at the time, with the node drift
+func (e Elements) Normal(t time.Time) [3]float64 {
+	raan, _, _ := e.secularElements(t.Sub(e.Epoch).Seconds())
+	incRad := deg2rad(e.Inclination)
+	return [3]float64{math.Sin(incRad) * math.Sin(raan), -math.Sin(incRad) * math.Cos(raan), math.Cos(incRad)}
+}
+
+// secularElements calculates the node, the argument of perigee and the mean anomaly [radians] after dt seconds
+func (e Elements) secularElements(dt float64) (raan float64, perigee float64, meanAnomaly float64) {
+	n := math.Sqrt(EarthGM / (e.SemiMajorAxis * e.SemiMajorAxis * e.SemiMajorAxis))
+	p := e.SemiMajorAxis * (1 - e.Eccentricity*e.Eccentricity)
+	factor := 0.75 * n * EarthJ2 * (EarthRadius / p) * (EarthRadius / p)
+	cosI := math.Cos(deg2rad(e.Inclination))
+
+	raan = deg2rad(e.RAAN) - 2*factor*cosI*dt
+	perigee = deg2rad(e.ArgumentOfPerigee) + factor*(5*cosI*cosI-1)*dt
+	meanAnomaly = deg2rad(e.MeanAnomaly) + (n+factor*math.Sqrt(1-e.Eccentricity*e.Eccentricity)*(3*cosI*cosI-1))*dt
+	return raan, perigee, math.Mod(meanAnomaly, 2*math.Pi)
+}
+
+// rotateZ rotates the vector about the z axis by the angle [radians]
+func rotateZ(v [3]float64, angle float64) [3]float64 {
+	sin, cos := math.Sincos(angle)
+	return [3]float64{cos*v[0] - sin*v[1], sin*v[0] + cos*v[1], v[2]}
+}
+
+// rotateX rotates the vector about the x axis by the angle [radians]
+func rotateX(v [3]float64, angle float64) [3]float64 {
+	sin, cos := math.Sincos(angle)
+	return [3]float64{v[0], cos*v[1] - sin*v[2], sin*v[1] + cos*v[2]}
+}
+
+func dot(a [3]float64, b [3]float64) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func cross(a [3]float64, b [3]float64) [3]float64 {
+	return [3]float64{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func norm(a [3]float64) float64 {
+	return math.Sqrt(dot(a, a))
+}
+
+func deg2rad(degrees float64) float64 {
+	return (math.Pi / 180.0) * degrees
+}
+
+func rad2deg(radians float64) float64 {
+	return (180.0 / math.Pi) * radians
+}
+
+func limitDegrees(degrees float64) float64 {
+	limited := math.Mod(degrees, 360.0)
+	if limited < 0 {
+		limited += 360.0
+	}
+	return limited
+}
@@ -0,0 +1,71 @@
+package orbit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var testEpoch = time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC)
+
+// Vallado, Fundamentals of Astrodynamics and Applications, example 2-6
+func TestStateVector(t *testing.T) {
+	const p = 11067.790
+	const ecc = 0.83285
+	nu := deg2rad(92.335)
+	eccentricAnomaly := 2 * math.Atan(math.Sqrt((1-ecc)/(1+ecc))*math.Tan(nu/2))
+	e := Elements{
+		Epoch:             testEpoch,
+		SemiMajorAxis:     p / (1 - ecc*ecc),
+		Eccentricity:      ecc,
+		Inclination:       87.87,
+		RAAN:              227.89,
+		ArgumentOfPerigee: 53.38,
+		MeanAnomaly:       rad2deg(eccentricAnomaly - ecc*math.Sin(eccentricAnomaly)),
+	}
+	sv := e.StateVector(testEpoch)
+	position := [3]float64{6525.344, 6861.535, 6449.125}
+	velocity := [3]float64{4.902276, 5.533124, -1.975709}
+	// the example rounds the angles to 0.01 degrees
+	for i := range position {
+		if math.Abs(sv.Position[i]-position[i]) > 0.05 || math.Abs(sv.Velocity[i]-velocity[i]) > 5e-5 {
+			t.Fatalf("got %v %v, want %v %v", sv.Position, sv.Velocity, position, velocity)
+		}
+	}
+}
+
+func TestElementsRoundTrip(t *testing.T) {
+	for _, e := range []Elements{
+		{testEpoch, 6798.137, 0.0005, 51.64, 123.4, 80.1, 200.5},
+		{testEpoch, 26560, 0.01, 55, 300, 270, 10},
+		{testEpoch, 24396, 0.73, 7, 45, 178, 359},
+	} {
+		got, err := ElementsFromStateVector(e.StateVector(testEpoch))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got.SemiMajorAxis-e.SemiMajorAxis) > 1e-6 || math.Abs(got.Eccentricity-e.Eccentricity) > 1e-10 ||
+			math.Abs(got.Inclination-e.Inclination) > 1e-9 || math.Abs(math.Remainder(got.RAAN-e.RAAN, 360)) > 1e-9 ||
+			math.Abs(math.Remainder(got.ArgumentOfPerigee-e.ArgumentOfPerigee, 360)) > 1e-6 ||
+			math.Abs(math.Remainder(got.MeanAnomaly-e.MeanAnomaly, 360)) > 1e-6 {
+			t.Errorf("got %+v, want %+v", got, e)
+		}
+	}
+
+	if _, err := ElementsFromStateVector(StateVector{Position: [3]float64{7000, 0, 0}, Velocity: [3]float64{0, 11, 0}}); err == nil {
+		t.Errorf("escape trajectory: no error")
+	}
+	if _, err := ElementsFromStateVector(StateVector{Position: [3]float64{7000, 0, 0}, Velocity: [3]float64{1, 0, 0}}); err == nil {
+		t.Errorf("radial trajectory: no error")
+	}
+}
+
+// the node of a sun-synchronous orbit follows the mean motion of the sun, 360 degrees per tropical year
+func TestNodeDrift(t *testing.T) {
+	e := Elements{Epoch: testEpoch, SemiMajorAxis: EarthRadius + 800, Inclination: 98.6}
+	normal := e.Normal(testEpoch.Add(24 * time.Hour))
+	raan := rad2deg(math.Atan2(normal[0], -normal[1]))
+	if want := 360 / 365.2422; math.Abs(raan-want) > 0.01 {
+		t.Errorf("got %v degrees per day, want %v", raan, want)
+	}
+}
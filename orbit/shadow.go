@@ -0,0 +1,219 @@
+package orbit
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	spa "github.com/maltegrosse/go-spa"
+)
+
+// Sun calculates the geocentric sun position with the SPA
+type Sun struct {
+	spa spa.Spa
+}
+
+// Eclipse defines an earth shadow passage. For the cylindrical shadow model the umbra is the whole shadow.
+// Passages which are cut by the search span start or end at the span limits.
+type Eclipse struct {
+	Start      time.Time // shadow entry (penumbra for the conical model)
+	End        time.Time // shadow exit (penumbra for the conical model)
+	UmbraStart time.Time // umbra entry, zero time without umbra
+	UmbraEnd   time.Time // umbra exit, zero time without umbra
+}
+
+// NewSun creates a sun ephemeris, the difference between earth rotation time and terrestrial time (deltaT)
+// is given in seconds
+func NewSun(deltaT float64) (*Sun, error) {
+	s, err := spa.NewSpa(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), 0, 0, 0, 1010, 10, deltaT, 0, 0, 0, 0.5667)
+	if err != nil {
+		return nil, err
+	}
+	s.SetSPAFunction(spa.SpaZa)
+	return &Sun{spa: s}, nil
+}
+
+// Position calculates the geocentric sun position [km] in the J2000 frame at the time
+func (sun *Sun) Position(t time.Time) ([3]float64, error) {
+	sun.spa.SetDate(t.UTC())
+	if err := sun.spa.Calculate(); err != nil {
+		return [3]float64{}, err
+	}
+	v := sun.spa.GetSunVectors()
+	return [3]float64{v.ECI[0] * v.DistanceKm, v.ECI[1] * v.DistanceKm, v.ECI[2] * v.DistanceKm}, nil
+}
+
+// BetaAngle calculates the solar beta angle [degrees] at the time, the angle between the sun direction
+// and the orbit plane, positive on the side of the orbit normal
+func (e Elements) BetaAngle(sun *Sun, t time.Time) (float64, error) {
+	position, err := sun.Position(t)
+	if err != nil {
+		return 0, err
+	}
+	return rad2deg(math.Asin(dot(e.Normal(t), position) / norm(position))), nil
+}
+
+// Illumination calculates the fraction of the solar disk visible from the spacecraft position [km], with the
+// geocentric sun position [km]: 1 in sunlight, 0 in the umbra and between in the penumbra
+func Illumination(position [3]float64, sunPosition [3]float64, model ShadowModels) float64 {
+	if model == ShadowCylindrical {
+		sunUnit := sunPosition
+		sunDistance := norm(sunPosition)
+		for i := range sunUnit {
+			sunUnit[i] /= sunDistance
+		}
+		along := dot(position, sunUnit)
+		if along >= 0 {
+			return 1
+		}
+		var perpendicular [3]float64
+		for i := range perpendicular {
+			perpendicular[i] = position[i] - along*sunUnit[i]
+		}
+		if norm(perpendicular) < EarthRadius {
+			return 0
+		}
+		return 1
+	}
+
+	// apparent radii of the sun and the earth and their separation as seen from the spacecraft
+	// (Montenbruck & Gill, Satellite Orbits, 3.4.2)
+	var toSun [3]float64
+	for i := range toSun {
+		toSun[i] = sunPosition[i] - position[i]
+	}
+	distance := norm(position)
+	sunDistance := norm(toSun)
+	a := math.Asin(math.Min(SunRadius/sunDistance, 1))
+	b := math.Asin(math.Min(EarthRadius/distance, 1))
+	c := math.Acos(math.Max(-1, math.Min(1, -dot(position, toSun)/(distance*sunDistance))))
+
+	switch {
+	case c >= a+b:
+		return 1
+	case c <= b-a:
+		return 0
+	case c <= a-b:
+		// earth disk inside the sun disk
+		return 1 - (b*b)/(a*a)
+	}
+	x := (c*c + a*a - b*b) / (2 * c)
+	y := math.Sqrt(math.Max(a*a-x*x, 0))
+	area := a*a*math.Acos(math.Max(-1, math.Min(1, x/a))) + b*b*math.Acos(math.Max(-1, math.Min(1, (c-x)/b))) - c*y
+	return 1 - area/(math.Pi*a*a)
+}
+
+// Illumination calculates the fraction of the solar disk visible from the spacecraft at the time
+func (e Elements) Illumination(sun *Sun, t time.Time, model ShadowModels) (float64, error) {
+	sunPosition, err := sun.Position(t)
+	if err != nil {
+		return 0, err
+	}
+	return Illumination(e.StateVector(t).Position, sunPosition, model), nil
+}
+
+// Eclipses searches the earth shadow passages from start to end, sampled with the step and refined to the millisecond.
+// The step must be shorter than the shortest shadow or umbra passage.
+func (e Elements) Eclipses(sun *Sun, start time.Time, end time.Time, step time.Duration, model ShadowModels) ([]Eclipse, error) {
+	var eclipses []Eclipse
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	if end.Before(start) {
+		return nil, errors.New("invalid time span")
+	}
+	if step <= 0 {
+		return nil, errors.New("invalid time step")
+	}
+
+	// state: 0 sunlight, 1 penumbra, 2 umbra
+	state := func(t time.Time) (int, error) {
+		fraction, err := e.Illumination(sun, t, model)
+		switch {
+		case err != nil:
+			return 0, err
+		case fraction >= 1:
+			return 0, nil
+		case fraction <= 0:
+			return 2, nil
+		}
+		return 1, nil
+	}
+	// refine bisects the instant between low and high when the shadow level changes
+	refine := func(low time.Time, high time.Time, shadow func(int) bool) (time.Time, error) {
+		level, err := state(low)
+		if err != nil {
+			return low, err
+		}
+		lowShadow := shadow(level)
+		for high.Sub(low) > time.Millisecond {
+			mid := low.Add(high.Sub(low) / 2)
+			level, err := state(mid)
+			if err != nil {
+				return mid, err
+			}
+			if shadow(level) == lowShadow {
+				low = mid
+			} else {
+				high = mid
+			}
+		}
+		return high, nil
+	}
+	inShadow := func(level int) bool { return level > 0 }
+	inUmbra := func(level int) bool { return level == 2 }
+
+	previous, err := state(start)
+	if err != nil {
+		return nil, err
+	}
+	var current Eclipse
+	if inShadow(previous) {
+		current.Start = start
+	}
+	if inUmbra(previous) {
+		current.UmbraStart = start
+	}
+	for t := start; t.Before(end); {
+		next := t.Add(step)
+		if next.After(end) {
+			next = end
+		}
+		level, err := state(next)
+		if err != nil {
+			return nil, err
+		}
+		if !inShadow(previous) && inShadow(level) {
+			if current.Start, err = refine(t, next, inShadow); err != nil {
+				return nil, err
+			}
+		}
+		if !inUmbra(previous) && inUmbra(level) {
+			if current.UmbraStart, err = refine(t, next, inUmbra); err != nil {
+				return nil, err
+			}
+		}
+		if inUmbra(previous) && !inUmbra(level) {
+			if current.UmbraEnd, err = refine(t, next, inUmbra); err != nil {
+				return nil, err
+			}
+		}
+		if inShadow(previous) && !inShadow(level) {
+			if current.End, err = refine(t, next, inShadow); err != nil {
+				return nil, err
+			}
+			eclipses = append(eclipses, current)
+			current = Eclipse{}
+		}
+		previous = level
+		t = next
+	}
+	if inShadow(previous) {
+		current.End = end
+		if inUmbra(previous) {
+			current.UmbraEnd = end
+		}
+		eclipses = append(eclipses, current)
+	}
+	return eclipses, nil
+}
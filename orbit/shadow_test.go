@@ -0,0 +1,80 @@
+package orbit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBetaAngle(t *testing.T) {
+	sun, err := NewSun(69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the equatorial orbit sees the sun at its declination
+	e := Elements{Epoch: testEpoch, SemiMajorAxis: EarthRadius + 420}
+	tests := []struct {
+		time time.Time
+		beta float64
+	}{
+		{testEpoch, 0},
+		{time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC), 23.44},
+		{time.Date(2024, 12, 21, 9, 21, 0, 0, time.UTC), -23.44},
+	}
+	for _, test := range tests {
+		beta, err := e.BetaAngle(sun, test.time)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// J2000 frame against the equinox of date
+		if math.Abs(beta-test.beta) > 0.2 {
+			t.Errorf("%v: got %v, want %v", test.time, beta, test.beta)
+		}
+	}
+}
+
+// an ISS-like orbit at zero beta angle spends about 36 minutes of its 93 minutes in the earth shadow
+func TestEclipses(t *testing.T) {
+	sun, err := NewSun(69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := Elements{Epoch: testEpoch, SemiMajorAxis: EarthRadius + 420, Inclination: 51.64}
+	if beta, err := e.BetaAngle(sun, testEpoch); err != nil || math.Abs(beta) > 0.5 {
+		t.Fatalf("beta angle: got %v, %v", beta, err)
+	}
+	// shadow arc of twice asin(R / r) of the orbit
+	period := 2 * math.Pi * math.Sqrt(math.Pow(e.SemiMajorAxis, 3)/EarthGM)
+	want := time.Duration(period * math.Asin(EarthRadius/e.SemiMajorAxis) / math.Pi * float64(time.Second))
+
+	for _, model := range []ShadowModels{ShadowCylindrical, ShadowConical} {
+		eclipses, err := e.Eclipses(sun, testEpoch, testEpoch.Add(3*time.Hour), 30*time.Second, model)
+		if err != nil {
+			t.Fatal(err)
+		}
+		full := 0
+		for _, eclipse := range eclipses {
+			if eclipse.Start.Equal(testEpoch) || eclipse.End.Equal(testEpoch.Add(3*time.Hour)) {
+				continue
+			}
+			full++
+			if d := eclipse.End.Sub(eclipse.Start); (d - want).Abs() > 30*time.Second {
+				t.Errorf("%v: got shadow of %v, want %v", model, d, want)
+			}
+			if model == ShadowConical {
+				umbra := eclipse.UmbraEnd.Sub(eclipse.UmbraStart)
+				penumbra := eclipse.End.Sub(eclipse.Start) - umbra
+				if umbra <= 0 || penumbra <= 0 || penumbra > time.Minute {
+					t.Errorf("got umbra %v and penumbra %v", umbra, penumbra)
+				}
+			}
+		}
+		if full < 1 {
+			t.Errorf("%v: got %v full eclipses, want at least 1", model, full)
+		}
+	}
+
+	if _, err := e.Eclipses(sun, testEpoch, testEpoch.Add(-time.Hour), time.Minute, ShadowConical); err == nil {
+		t.Errorf("invalid time span: no error")
+	}
+}
@@ -0,0 +1,35 @@
+package spa
+
+import "math"
+
+// Carrington elements of the solar rotation
+const (
+	carringtonInclination = 7.25         // inclination of the solar equator on the ecliptic [degrees]
+	carringtonPeriod      = 25.38        // sidereal rotation period [days]
+	carringtonSynodic     = 27.2752316   // mean synodic rotation period [days]
+	carringtonEpoch       = 2398140.2270 // julian ephemeris day of the start of the rotation 0
+)
+
+// solarPhysicalEphemeris calculates the position angle P of the solar rotation axis, the heliographic latitude B0
+// and longitude L0 of the disk center and the Carrington rotation number (Meeus, Astronomical Algorithms, chapter 29)
+func (s *spa) solarPhysicalEphemeris() {
+	theta := s.limitDegrees((s.jde - 2398220.0) * 360.0 / carringtonPeriod)
+	inclination := s.deg2rad(carringtonInclination)
+	// longitude of the ascending node of the solar equator on the ecliptic
+	k := s.deg2rad(73.6667 + 1.3958333*(s.jde-2396758.0)/36525.0)
+
+	// apparent sun longitude with and without the nutation
+	lambdaPrime := s.deg2rad(s.lamda)
+	lambda := s.deg2rad(s.lamda - s.delPsi)
+
+	x := math.Atan(-math.Cos(lambdaPrime) * math.Tan(s.deg2rad(s.epsilon)))
+	y := math.Atan(-math.Cos(lambda-k) * math.Tan(inclination))
+	s.positionAngle = s.rad2deg(x + y)
+	s.b0 = s.rad2deg(math.Asin(math.Sin(lambda-k) * math.Sin(inclination)))
+	eta := math.Atan2(-math.Sin(lambda-k)*math.Cos(inclination), -math.Cos(lambda-k))
+	s.l0 = s.limitDegrees(s.rad2deg(eta) - theta)
+
+	// the rotation starts at L0 = 360 degrees, the mean period resolves the number
+	fraction := 1 - s.l0/360.0
+	s.carrington = math.Round((s.jde-carringtonEpoch)/carringtonSynodic-fraction) + fraction
+}
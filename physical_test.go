@@ -0,0 +1,40 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// Meeus, Astronomical Algorithms, example 29.a (1992 October 13.0 TD)
+func TestSolarPhysicalEphemeris(t *testing.T) {
+	s := newTestSpa(t)
+	s.SetDate(time.Date(1992, 10, 13, 0, 0, 0, 0, time.UTC))
+	s.SetDeltaT(0)
+	s.SetSPAFunction(SpaZa)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"P", s.GetSolarP(), 26.27},
+		{"B0", s.GetSolarB0(), 5.99},
+		{"L0", s.GetSolarL0(), 238.64},
+	}
+	for _, test := range tests {
+		if math.Abs(test.got-test.want) > 0.005 {
+			t.Errorf("%v: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+	// the fraction of the Carrington rotation counts from L0 = 360 degrees
+	if fraction := s.GetCarringtonRotation() - math.Floor(s.GetCarringtonRotation()); math.Abs(fraction-(1-s.GetSolarL0()/360)) > 1e-9 {
+		t.Errorf("Carrington rotation %v does not match L0 %v", s.GetCarringtonRotation(), s.GetSolarL0())
+	}
+	// semi-diameter of 959.63 arc seconds at 1 AU
+	if want := 959.63 / 3600 / s.GetR(); math.Abs(s.GetSunSemiDiameter()-want) > 1e-12 {
+		t.Errorf("semi-diameter: got %v, want %v", s.GetSunSemiDiameter(), want)
+	}
+}
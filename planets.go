@@ -0,0 +1,93 @@
+package spa
+
+import (
+	"errors"
+	"math"
+)
+
+// light time for one Astronomical Unit [days]
+const lightTimeAU = 0.0057755183
+
+// PlanetPosition holds the position of a planet for the observer and date of a calculated instance
+type PlanetPosition struct {
+	HeliocentricLongitude     float64 // heliocentric longitude at the emission of the light [degrees]
+	HeliocentricLatitude      float64 // heliocentric latitude at the emission of the light [degrees]
+	HeliocentricRadius        float64 // distance from the sun at the emission of the light [AU]
+	Distance                  float64 // geocentric distance [AU]
+	LightTime                 float64 // light time from the planet to the earth [days]
+	Longitude                 float64 // apparent geocentric longitude [degrees]
+	Latitude                  float64 // apparent geocentric latitude [degrees]
+	RightAscension            float64 // apparent geocentric right ascension [degrees]
+	Declination               float64 // apparent geocentric declination [degrees]
+	HourAngle                 float64 // topocentric local hour angle [degrees]
+	TopocentricRightAscension float64 // topocentric right ascension [degrees]
+	TopocentricDeclination    float64 // topocentric declination [degrees]
+	Elevation                 float64 // topocentric elevation angle (uncorrected) [degrees]
+	ApparentElevation         float64 // topocentric elevation angle (corrected) [degrees]
+	Azimuth                   float64 // topocentric azimuth angle (eastward from north) [degrees]
+	Refraction                float64 // atmospheric refraction correction [degrees]
+}
+
+// planetPosition calculates the apparent position of the planet from its VSOP87 version D series at the date
+// of the instance (Meeus, Astronomical Algorithms, chapter 33). The earth position, the nutation, the obliquity
+// and the sidereal time of the sun calculation are reused, so Calculate must be called before.
+func (s *spa) planetPosition(planet *VSOP87) (PlanetPosition, error) {
+	var p PlanetPosition
+	if planet == nil {
+		return p, errors.New("missing planet series")
+	}
+	if planet.GetBody() == "EARTH" || planet.GetBody() == "EMB" {
+		return p, errors.New("the earth has no geocentric position")
+	}
+	if s.jd == 0 {
+		return p, errors.New("solar position is not calculated")
+	}
+
+	// geometric position of the planet at the emission of the light, iterated for the light time
+	l0, b0 := s.deg2rad(s.l), s.deg2rad(s.b)
+	var x, y, z float64
+	for i := 0; i < 10; i++ {
+		l, b, r := planet.Position(s.jme - p.LightTime/365250.0)
+		p.HeliocentricLongitude, p.HeliocentricLatitude, p.HeliocentricRadius = l, b, r
+		lRad, bRad := s.deg2rad(l), s.deg2rad(b)
+		x = r*math.Cos(bRad)*math.Cos(lRad) - s.r*math.Cos(b0)*math.Cos(l0)
+		y = r*math.Cos(bRad)*math.Sin(lRad) - s.r*math.Cos(b0)*math.Sin(l0)
+		z = r*math.Sin(bRad) - s.r*math.Sin(b0)
+		distance := math.Sqrt(x*x + y*y + z*z)
+		lightTime := lightTimeAU * distance
+		done := math.Abs(lightTime-p.LightTime) < 1e-9
+		p.Distance, p.LightTime = distance, lightTime
+		if done {
+			break
+		}
+	}
+	lambda := math.Atan2(y, x)
+	beta := math.Atan2(z, math.Sqrt(x*x+y*y))
+
+	// annual aberration with the true longitude of the sun, the eccentricity and the perihelion of the earth orbit
+	kappa := s.deg2rad(20.49552 / 3600.0)
+	e := 0.016708634 - 0.000042037*s.jce - 0.0000001267*s.jce*s.jce
+	pi := s.deg2rad(102.93735 + 1.71946*s.jce + 0.00046*s.jce*s.jce)
+	sun := s.deg2rad(s.theta)
+	delLambda := (-kappa*math.Cos(sun-lambda) + e*kappa*math.Cos(pi-lambda)) / math.Cos(beta)
+	delBeta := -kappa * math.Sin(beta) * (math.Sin(sun-lambda) - e*math.Sin(pi-lambda))
+
+	p.Longitude = s.limitDegrees(s.rad2deg(lambda+delLambda) + s.delPsi)
+	p.Latitude = s.rad2deg(beta + delBeta)
+	p.RightAscension = s.geocentricRightAscension(p.Longitude, s.epsilon, p.Latitude)
+	p.Declination = s.geocentricDeclination(p.Latitude, s.epsilon, p.Longitude)
+
+	// topocentric stage of the sun calculation on a copy
+	t := *s
+	h := t.observerHourAngle(t.nu, t.longitude, p.RightAscension)
+	t.rightAscensionParallaxAndTopocentricDec(t.latitude, t.elevation, t.sunEquatorialHorizontalParallax(p.Distance), h, p.Declination)
+	p.TopocentricRightAscension = t.limitDegrees(t.topocentricRightAscension(p.RightAscension, t.delAlpha))
+	p.TopocentricDeclination = t.deltaPrime
+	p.HourAngle = t.topocentricLocalHourAngle(h, t.delAlpha)
+
+	p.Elevation = t.topocentricElevationAngle(t.latitude, p.TopocentricDeclination, p.HourAngle)
+	p.Refraction = t.refraction(p.Elevation)
+	p.ApparentElevation = t.topocentricElevationAngleCorrected(p.Elevation, p.Refraction)
+	p.Azimuth = t.topocentricAzimuthAngle(t.topocentricAzimuthAngleAstro(p.HourAngle, t.latitude, p.TopocentricDeclination))
+	return p, nil
+}
@@ -0,0 +1,23 @@
+package spa
+
+// SolarPosition holds the topocentric sun position with and without the atmospheric refraction,
+// like the apparent and geometric outputs of common irradiance models
+type SolarPosition struct {
+	ApparentZenith    float64 // topocentric zenith angle (corrected) [degrees]
+	Zenith            float64 // topocentric zenith angle (uncorrected) [degrees]
+	ApparentElevation float64 // topocentric elevation angle (corrected) [degrees]
+	Elevation         float64 // topocentric elevation angle (uncorrected) [degrees]
+	Azimuth           float64 // topocentric azimuth angle (eastward from north) [degrees]
+	Refraction        float64 // atmospheric refraction correction [degrees]
+}
+
+func (s *spa) solarPosition() SolarPosition {
+	return SolarPosition{
+		ApparentZenith:    s.zenith,
+		Zenith:            s.zenith0,
+		ApparentElevation: s.e,
+		Elevation:         s.e0,
+		Azimuth:           s.azimuth,
+		Refraction:        s.delE,
+	}
+}
@@ -0,0 +1,35 @@
+package spa
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolarPosition(t *testing.T) {
+	s := newTestSpa(t)
+	if err := s.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	p := s.GetSolarPosition()
+	want := SolarPosition{
+		ApparentZenith:    s.GetZenith(),
+		Zenith:            s.GetZenith0(),
+		ApparentElevation: s.GetE(),
+		Elevation:         s.GetE0(),
+		Azimuth:           s.GetAzimuth(),
+		Refraction:        s.GetDelE(),
+	}
+	if p != want {
+		t.Errorf("got %+v, want %+v", p, want)
+	}
+	// NREL SPA example output
+	if math.Abs(p.ApparentZenith-50.11162) > 1e-5 || math.Abs(p.Azimuth-194.34024) > 1e-5 {
+		t.Errorf("got zenith %v and azimuth %v, want 50.11162 and 194.34024", p.ApparentZenith, p.Azimuth)
+	}
+	if math.Abs(p.Zenith-(90-p.Elevation)) > 1e-12 || math.Abs(p.ApparentZenith-(90-p.ApparentElevation)) > 1e-12 {
+		t.Errorf("got zenith %v and %v, elevation %v and %v", p.Zenith, p.ApparentZenith, p.Elevation, p.ApparentElevation)
+	}
+	if math.Abs(p.Zenith-p.ApparentZenith-p.Refraction) > 1e-12 || p.Refraction <= 0 {
+		t.Errorf("got refraction %v, zenith %v and apparent zenith %v", p.Refraction, p.Zenith, p.ApparentZenith)
+	}
+}
@@ -0,0 +1,23 @@
+package prayer
+
+// AsrJuristics defines the shadow length rule for the Asr prayer
+type AsrJuristics uint32
+
+// enumeration for the Asr shadow length rules
+//go:generate stringer -type=AsrJuristics
+const (
+	AsrShafii AsrJuristics = 0 //shadow length equals the object height plus the noon shadow (Shafi'i, Maliki, Hanbali)
+	AsrHanafi AsrJuristics = 1 //shadow length equals twice the object height plus the noon shadow (Hanafi)
+)
+
+// HighLatitudeRules defines the adjustment of Fajr and Isha when twilight persists through the night
+type HighLatitudeRules uint32
+
+// enumeration for high latitude adjustment rules
+//go:generate stringer -type=HighLatitudeRules
+const (
+	HighLatitudeNone       HighLatitudeRules = 0 //no adjustment, missing times are reported as error
+	HighLatitudeMiddle     HighLatitudeRules = 1 //Fajr and Isha at most half of the night from sunrise and sunset
+	HighLatitudeOneSeventh HighLatitudeRules = 2 //Fajr and Isha at most one seventh of the night from sunrise and sunset
+	HighLatitudeAngleBased HighLatitudeRules = 3 //Fajr and Isha at most angle/60 of the night from sunrise and sunset
+)
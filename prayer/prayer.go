@@ -0,0 +1,133 @@
+// Package prayer calculates the Islamic prayer times from the solar position of the SPA.
+//
+// The times are derived from the sun transit and the sunrise, sunset and custom elevation
+// crossings of a calculated spa.Spa instance, so the observer settings of the instance
+// (horizon dip, sunrise/sunset definition, atmospheric refraction) are applied as well.
+package prayer
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	spa "github.com/maltegrosse/go-spa"
+)
+
+// Method defines the twilight angles and intervals of a calculation convention
+type Method struct {
+	Name         string
+	FajrAngle    float64       // sun depression below the horizon at Fajr [degrees]
+	IshaAngle    float64       // sun depression below the horizon at Isha [degrees], unused with IshaInterval
+	IshaInterval time.Duration // fixed interval between Maghrib and Isha, zero to use IshaAngle
+	MaghribAngle float64       // sun depression below the horizon at Maghrib [degrees], zero for sunset
+}
+
+// calculation conventions
+var (
+	MuslimWorldLeague = Method{Name: "Muslim World League", FajrAngle: 18, IshaAngle: 17}
+	ISNA              = Method{Name: "Islamic Society of North America", FajrAngle: 15, IshaAngle: 15}
+	Egyptian          = Method{Name: "Egyptian General Authority of Survey", FajrAngle: 19.5, IshaAngle: 17.5}
+	UmmAlQura         = Method{Name: "Umm al-Qura University, Makkah", FajrAngle: 18.5, IshaInterval: 90 * time.Minute}
+	Karachi           = Method{Name: "University of Islamic Sciences, Karachi", FajrAngle: 18, IshaAngle: 18}
+	Tehran            = Method{Name: "Institute of Geophysics, University of Tehran", FajrAngle: 17.7, IshaAngle: 14, MaghribAngle: 4.5}
+	Jafari            = Method{Name: "Shia Ithna-Ashari, Leva Institute, Qum", FajrAngle: 16, IshaAngle: 14, MaghribAngle: 4}
+)
+
+// Times holds the prayer times of a day in the time zone of the spa.Spa instance
+type Times struct {
+	Fajr    time.Time
+	Sunrise time.Time
+	Dhuhr   time.Time
+	Asr     time.Time
+	Maghrib time.Time
+	Isha    time.Time
+}
+
+// Calculate calculates the prayer times of the day of the spa.Spa instance. The instance must be
+// calculated with the spa.SpaZaRts or spa.SpaAll function.
+func Calculate(s spa.Spa, method Method, asr AsrJuristics, rule HighLatitudeRules) (Times, error) {
+	var times Times
+	var err error
+
+	if s.GetSta() == -99999 {
+		// no sunrise or sunset, the night portions are undefined
+		return times, errors.New("no sunrise or sunset on this day")
+	}
+	times.Sunrise = s.GetSunrise()
+	times.Dhuhr = s.GetSuntransitTime()
+
+	// Asr: shadow length equals the noon shadow plus the object height times the shadow factor,
+	// the shadow altitude is measured from the true horizontal without the horizon dip
+	shadowFactor := 1.0
+	if asr == AsrHanafi {
+		shadowFactor = 2.0
+	}
+	asrAltitude := rad2deg(math.Atan(1 / (shadowFactor + 1/math.Tan(deg2rad(s.GetSta())))))
+	if _, times.Asr, err = s.GetAltitudeCrossing(asrAltitude); err != nil {
+		return times, err
+	}
+
+	times.Maghrib = s.GetSunset()
+	if method.MaghribAngle > 0 {
+		if _, times.Maghrib, err = s.GetElevationCrossing(-method.MaghribAngle); err != nil {
+			return times, err
+		}
+	}
+
+	// night from sunset to the next sunrise
+	night := time.Duration((24 - s.GetDayLength()) * float64(time.Hour))
+
+	fajr, _, fajrErr := s.GetElevationCrossing(-method.FajrAngle)
+	times.Fajr, err = adjustHighLatitude(fajr, fajrErr, times.Sunrise, -1, night, method.FajrAngle, rule)
+	if err != nil {
+		return times, err
+	}
+
+	if method.IshaInterval > 0 {
+		times.Isha = times.Maghrib.Add(method.IshaInterval)
+		return times, nil
+	}
+	_, isha, ishaErr := s.GetElevationCrossing(-method.IshaAngle)
+	times.Isha, err = adjustHighLatitude(isha, ishaErr, s.GetSunset(), 1, night, method.IshaAngle, rule)
+	if err != nil {
+		return times, err
+	}
+
+	return times, nil
+}
+
+// adjustHighLatitude limits the twilight time to a portion of the night before sunrise (direction -1)
+// or after sunset (direction 1)
+func adjustHighLatitude(twilight time.Time, twilightErr error, base time.Time, direction int, night time.Duration, angle float64, rule HighLatitudeRules) (time.Time, error) {
+	var portion float64
+	switch rule {
+	case HighLatitudeMiddle:
+		portion = 1.0 / 2.0
+	case HighLatitudeOneSeventh:
+		portion = 1.0 / 7.0
+	case HighLatitudeAngleBased:
+		portion = angle / 60.0
+	default:
+		return twilight, twilightErr
+	}
+	limit := base.Add(time.Duration(direction) * time.Duration(portion*float64(night))).Truncate(time.Second)
+	if twilightErr != nil {
+		if twilightErr != spa.ErrNoCrossing {
+			return twilight, twilightErr
+		}
+		return limit, nil
+	}
+	if (direction < 0 && twilight.Before(limit)) || (direction > 0 && twilight.After(limit)) ||
+		(direction < 0 && twilight.After(base)) || (direction > 0 && twilight.Before(base)) {
+		return limit, nil
+	}
+	return twilight, nil
+}
+
+func deg2rad(degrees float64) float64 {
+	return (math.Pi / 180.0) * degrees
+}
+
+func rad2deg(radians float64) float64 {
+	return (180.0 / math.Pi) * radians
+}
@@ -0,0 +1,192 @@
+package prayer
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	spa "github.com/maltegrosse/go-spa"
+)
+
+// Makkah timetable of the PrayTimes.org calculation (version 2.3) for 2024, rounded to minutes
+var makkahTimetable = []struct {
+	date    string
+	sunrise string
+	dhuhr   string
+	shafii  string // Asr of the Shafi'i, Maliki and Hanbali schools
+	hanafi  string // Asr of the Hanafi school
+}{
+	{"2024-01-15", "07:01", "12:30", "15:37", "16:23"},
+	{"2024-04-15", "06:01", "12:21", "15:45", "16:51"},
+	{"2024-07-15", "05:47", "12:27", "15:41", "17:02"},
+	{"2024-10-15", "06:17", "12:06", "15:27", "16:19"},
+}
+
+func TestCalculateMakkah(t *testing.T) {
+	zone := time.FixedZone("AST", 3*3600)
+	for _, day := range makkahTimetable {
+		date, err := time.ParseInLocation("2006-01-02", day.date, zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clock := func(hhmm string) time.Time {
+			c, err := time.ParseInLocation("2006-01-02 15:04", day.date+" "+hhmm, zone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return c
+		}
+		for _, height := range []float64{0, 300} {
+			s, err := spa.NewSpa(date.Add(12*time.Hour), 21.4225, 39.8262, 277, math.NaN(), math.NaN(), 69.2, 0, 0, 0, 0.5667)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.SetSPAFunction(spa.SpaZaRts)
+			s.SetHorizonDip(spa.DipGeometric)
+			s.SetObserverHeight(height)
+			if err := s.Calculate(); err != nil {
+				t.Fatal(err)
+			}
+			shafii, err := Calculate(s, UmmAlQura, AsrShafii, HighLatitudeNone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hanafi, err := Calculate(s, UmmAlQura, AsrHanafi, HighLatitudeNone)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the shadow altitudes of Dhuhr and Asr do not depend on the observer height
+			check := func(name string, got time.Time, want string) {
+				if d := got.Sub(clock(want)); d < -time.Minute || d > time.Minute {
+					t.Errorf("%v %v at %v m: got %v, want %v", day.date, name, height, got.Format("15:04:05"), want)
+				}
+			}
+			check("Dhuhr", shafii.Dhuhr, day.dhuhr)
+			check("Asr Shafi'i", shafii.Asr, day.shafii)
+			check("Asr Hanafi", hanafi.Asr, day.hanafi)
+			if height == 0 {
+				check("Sunrise", shafii.Sunrise, day.sunrise)
+			} else if !shafii.Sunrise.Before(clock(day.sunrise).Add(-time.Minute)) {
+				// the dip of the horizon makes the sunrise earlier
+				t.Errorf("%v Sunrise at %v m: got %v, want before %v", day.date, height, shafii.Sunrise.Format("15:04:05"), day.sunrise)
+			}
+		}
+	}
+}
+
+// the times of the sun events are the times of the instance to the second
+func TestCalculateSunEvents(t *testing.T) {
+	s, err := spa.NewSpa(time.Date(2024, 3, 20, 12, 0, 0, 0, time.FixedZone("AST", 3*3600)),
+		21.4225, 39.8262, 277, math.NaN(), math.NaN(), 69.2, 0, 0, 0, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	times, err := Calculate(s, MuslimWorldLeague, AsrShafii, HighLatitudeNone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !times.Dhuhr.Equal(s.GetSuntransitTime()) || !times.Sunrise.Equal(s.GetSunrise()) || !times.Maghrib.Equal(s.GetSunset()) {
+		t.Errorf("got Sunrise %v, Dhuhr %v and Maghrib %v, want %v, %v and %v", times.Sunrise, times.Dhuhr, times.Maghrib,
+			s.GetSunrise(), s.GetSuntransitTime(), s.GetSunset())
+	}
+}
+
+// timetables of the PrayTimes.org calculation (version 2.3) for 2024, rounded to minutes
+var methodTimetables = []struct {
+	city      string
+	latitude  float64
+	longitude float64
+	method    Method
+	date      string
+	zone      float64   // hours
+	times     [6]string // Fajr, Sunrise, Dhuhr, Asr (Shafi'i), Maghrib, Isha
+}{
+	{"London", 51.5074, -0.1278, MuslimWorldLeague, "2024-01-15", 0, [6]string{"05:59", "08:00", "12:10", "14:01", "16:20", "18:14"}},
+	{"London", 51.5074, -0.1278, MuslimWorldLeague, "2024-03-20", 0, [6]string{"04:09", "06:02", "12:08", "15:26", "18:14", "20:01"}},
+	{"London", 51.5074, -0.1278, MuslimWorldLeague, "2024-10-15", 1, [6]string{"05:34", "07:25", "12:46", "15:32", "18:06", "19:51"}},
+	{"New York", 40.7128, -74.006, ISNA, "2024-01-15", -5, [6]string{"05:58", "07:18", "12:05", "14:34", "16:53", "18:13"}},
+	{"New York", 40.7128, -74.006, ISNA, "2024-04-15", -4, [6]string{"04:56", "06:16", "12:56", "16:40", "19:36", "20:56"}},
+	{"New York", 40.7128, -74.006, ISNA, "2024-07-15", -4, [6]string{"04:03", "05:38", "13:02", "17:00", "20:26", "22:01"}},
+	{"Makkah", 21.4225, 39.8262, UmmAlQura, "2024-01-15", 3, [6]string{"05:41", "07:01", "12:30", "15:37", "17:59", "19:29"}},
+	{"Makkah", 21.4225, 39.8262, UmmAlQura, "2024-07-15", 3, [6]string{"04:21", "05:47", "12:27", "15:41", "19:06", "20:36"}},
+}
+
+// newDay creates the instance at the local noon of the date
+func newDay(t *testing.T, latitude float64, longitude float64, date string, zone float64) spa.Spa {
+	t.Helper()
+	day, err := time.ParseInLocation("2006-01-02", date, time.FixedZone("", int(zone*3600)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := spa.NewSpa(day.Add(12*time.Hour), latitude, longitude, 0, 1010, 10, 69.2, 0, 0, 0, 0.5667)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// checkTime compares the time with the timetable entry "15:04" within a minute, the entry is a clock time
+// which may fall on the next day
+func checkTime(t *testing.T, s spa.Spa, name string, got time.Time, want string) {
+	t.Helper()
+	clock, err := time.ParseInLocation("2006-01-02 15:04", s.GetDate().Format("2006-01-02 ")+want, s.GetDate().Location())
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := got.Sub(clock)
+	if d > 12*time.Hour {
+		d -= 24 * time.Hour
+	}
+	if d < -time.Minute || d > time.Minute {
+		t.Errorf("%v %v: got %v, want %v", s.GetDate().Format("2006-01-02"), name, got.Format("15:04:05"), want)
+	}
+}
+
+func TestCalculateMethods(t *testing.T) {
+	for _, day := range methodTimetables {
+		s := newDay(t, day.latitude, day.longitude, day.date, day.zone)
+		times, err := Calculate(s, day.method, AsrShafii, HighLatitudeNone)
+		if err != nil {
+			t.Fatalf("%v %v: %v", day.city, day.date, err)
+		}
+		for i, got := range []time.Time{times.Fajr, times.Sunrise, times.Dhuhr, times.Asr, times.Maghrib, times.Isha} {
+			name := day.city + " " + []string{"Fajr", "Sunrise", "Dhuhr", "Asr", "Maghrib", "Isha"}[i]
+			checkTime(t, s, name, got, day.times[i])
+		}
+	}
+}
+
+// London with the Muslim World League angles: the twilight persists through the night of 21 June,
+// on 10 May the rules limit the twilight times which exist
+func TestCalculateHighLatitude(t *testing.T) {
+	tests := []struct {
+		date string
+		rule HighLatitudeRules
+		fajr string
+		isha string
+	}{
+		{"2024-06-21", HighLatitudeMiddle, "01:02", "01:02"},
+		{"2024-06-21", HighLatitudeOneSeventh, "03:40", "22:25"},
+		{"2024-06-21", HighLatitudeAngleBased, "02:31", "23:27"},
+		{"2024-05-10", HighLatitudeNone, "02:27", "23:13"},
+		{"2024-05-10", HighLatitudeMiddle, "02:27", "23:13"},
+		{"2024-05-10", HighLatitudeOneSeventh, "04:02", "21:53"},
+		{"2024-05-10", HighLatitudeAngleBased, "02:41", "23:05"},
+	}
+	for _, test := range tests {
+		s := newDay(t, 51.5074, -0.1278, test.date, 1)
+		times, err := Calculate(s, MuslimWorldLeague, AsrShafii, test.rule)
+		if err != nil {
+			t.Fatalf("%v %v: %v", test.date, test.rule, err)
+		}
+		checkTime(t, s, test.rule.String()+" Fajr", times.Fajr, test.fajr)
+		checkTime(t, s, test.rule.String()+" Isha", times.Isha, test.isha)
+	}
+
+	// without a rule the missing twilight is reported
+	s := newDay(t, 51.5074, -0.1278, "2024-06-21", 1)
+	if _, err := Calculate(s, MuslimWorldLeague, AsrShafii, HighLatitudeNone); err != spa.ErrNoCrossing {
+		t.Errorf("2024-06-21 without a rule: got %v, want %v", err, spa.ErrNoCrossing)
+	}
+}
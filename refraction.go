@@ -0,0 +1,336 @@
+package spa
+
+import (
+	"math"
+)
+
+// RefractionConditions describes the atmosphere at the observer for a refraction model
+type RefractionConditions struct {
+	Pressure     float64 // local pressure [millibars]
+	Temperature  float64 // local temperature [degrees Celsius]
+	Elevation    float64 // observer elevation above sea level [meters]
+	Height       float64 // observer height above the surrounding terrain or sea [meters]
+	AtmosRefract float64 // atmospheric refraction at sunrise and sunset [degrees]
+}
+
+// RefractionModel calculates the atmospheric refraction correction
+type RefractionModel interface {
+	// Refraction returns the correction [degrees] which is added to the true (geometric) elevation e0 [degrees]
+	// to get the apparent elevation
+	Refraction(e0 float64, c RefractionConditions) float64
+	// InverseRefraction returns the correction [degrees] which is subtracted from the apparent (observed)
+	// elevation e [degrees] to get the true elevation, it is the exact inverse of Refraction
+	InverseRefraction(e float64, c RefractionConditions) float64
+}
+
+// ApparentElevation converts the true (geometric) elevation e0 [degrees] to the apparent elevation [degrees]
+func ApparentElevation(model RefractionModel, e0 float64, c RefractionConditions) float64 {
+	return e0 + model.Refraction(e0, c)
+}
+
+// TrueElevation converts the apparent (observed) elevation e [degrees] to the true (geometric) elevation [degrees]
+func TrueElevation(model RefractionModel, e float64, c RefractionConditions) float64 {
+	return e - model.InverseRefraction(e, c)
+}
+
+// SpaRefraction is the refraction formula of the SPA, switched off below the elevation -(SunRadius + AtmosRefract)
+type SpaRefraction struct{}
+
+// SaemundssonRefraction is the formula of Saemundsson (1986) for the true elevation, scaled by pressure and
+// temperature. Like the SPA formula it is switched off below the elevation -(SunRadius + AtmosRefract),
+// the solar limbs are refracted down to its maximum at about -1.9 degrees and held there.
+type SaemundssonRefraction struct{}
+
+// BennettRefraction is the formula of Bennett (1982) for the apparent elevation, scaled by pressure and
+// temperature and inverted iteratively for the true elevation. Like the SPA formula it is switched off below
+// the elevation -(SunRadius + AtmosRefract), the solar limbs are refracted down to its maximum at about
+// -1.7 degrees and held there.
+type BennettRefraction struct{}
+
+// lowest elevations [degrees] of the refraction formulas, where the refraction is at its maximum
+var (
+	saemundssonMinElevation = math.Sqrt(10.3) - 5.11
+	bennettMinElevation     = math.Sqrt(7.31) - 4.4
+)
+
+// NoRefraction ignores the atmospheric refraction
+type NoRefraction struct{}
+
+// RayTraceRefraction integrates the refraction numerically along the ray through a standard atmosphere
+// (Auer & Standish, 2000), which starts at the observer elevation with the local pressure and temperature.
+// It is valid below the horizon, as seen from an observer above the ground (Height). Rays which hit the
+// ground are not refracted, the sun is hidden behind the earth.
+type RayTraceRefraction struct {
+	Steps int // integration steps per ray segment, 0 for the default of 128
+}
+
+func (SpaRefraction) Refraction(e0 float64, c RefractionConditions) float64 {
+	var s spa
+	return s.atmosphericRefractionCorrection(c.Pressure, c.Temperature, c.AtmosRefract, e0)
+}
+
+// InverseRefraction of the SPA formula: apparent elevations below the cut-off are not refracted, apparent
+// elevations between the cut-off and the refracted cut-off have no true elevation and are treated alike.
+func (SpaRefraction) InverseRefraction(e float64, c RefractionConditions) float64 {
+	var s spa
+	correction := iterateInverseRefraction(e, func(e0 float64) float64 {
+		return s.limbRefractionCorrection(c.Pressure, c.Temperature, e0)
+	})
+	if e-correction < refractionCutOff(c) {
+		return 0
+	}
+	return correction
+}
+
+func (m SaemundssonRefraction) Refraction(e0 float64, c RefractionConditions) float64 {
+	if e0 < refractionCutOff(c) {
+		return 0
+	}
+	return m.limbRefraction(e0, c)
+}
+
+func (m SaemundssonRefraction) InverseRefraction(e float64, c RefractionConditions) float64 {
+	correction := iterateInverseRefraction(e, func(e0 float64) float64 {
+		return m.limbRefraction(e0, c)
+	})
+	if e-correction < refractionCutOff(c) {
+		return 0
+	}
+	return correction
+}
+
+// limbRefraction calculates the refraction [degrees] for the true elevation e0 [degrees] without the cut-off
+func (SaemundssonRefraction) limbRefraction(e0 float64, c RefractionConditions) float64 {
+	var s spa
+	return s.limbRefractionCorrection(c.Pressure, c.Temperature, math.Max(e0, saemundssonMinElevation))
+}
+
+func (m BennettRefraction) Refraction(e0 float64, c RefractionConditions) float64 {
+	if e0 < refractionCutOff(c) {
+		return 0
+	}
+	return m.limbRefraction(e0, c)
+}
+
+func (m BennettRefraction) InverseRefraction(e float64, c RefractionConditions) float64 {
+	correction := m.apparentRefraction(e, c)
+	if e-correction < refractionCutOff(c) {
+		return 0
+	}
+	return correction
+}
+
+// limbRefraction calculates the refraction [degrees] for the true elevation e0 [degrees] without the cut-off
+func (m BennettRefraction) limbRefraction(e0 float64, c RefractionConditions) float64 {
+	return iterateRefraction(e0, func(e float64) float64 {
+		return m.apparentRefraction(e, c)
+	})
+}
+
+// apparentRefraction calculates the refraction [degrees] for the apparent elevation e [degrees]
+func (BennettRefraction) apparentRefraction(e float64, c RefractionConditions) float64 {
+	var s spa
+	e = math.Max(e, bennettMinElevation)
+	return (c.Pressure / 1010.0) * (283.0 / (273.0 + c.Temperature)) / (60.0 * math.Tan(s.deg2rad(e+7.31/(e+4.4))))
+}
+
+func (NoRefraction) Refraction(e0 float64, c RefractionConditions) float64 {
+	return 0
+}
+
+func (NoRefraction) InverseRefraction(e float64, c RefractionConditions) float64 {
+	return 0
+}
+
+func (m RayTraceRefraction) Refraction(e0 float64, c RefractionConditions) float64 {
+	// true elevations below the grazing ray are hidden behind the earth, above it the iteration
+	// starts at the grazing ray
+	grazing := m.grazingElevation(c)
+	if e0 < grazing-m.apparentRefraction(grazing, c) {
+		return 0
+	}
+	refraction := iterateRefraction(e0, func(e float64) float64 {
+		return m.apparentRefraction(math.Max(e, grazing), c)
+	})
+	e := e0 + refraction
+	if e >= grazing && math.Abs(e-m.apparentRefraction(e, c)-e0) < 1e-9 {
+		return refraction
+	}
+
+	// close to the grazing ray the refraction changes faster than the elevation and the iteration
+	// does not converge, the true elevation e - refraction(e) increases with e and is bisected
+	low, high := grazing, 90.0
+	for high-low > 1e-10 {
+		mid := (low + high) / 2
+		if mid-m.apparentRefraction(mid, c) < e0 {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return high - e0
+}
+
+func (m RayTraceRefraction) InverseRefraction(e float64, c RefractionConditions) float64 {
+	return m.apparentRefraction(e, c)
+}
+
+// refractionCutOff returns the true elevation [degrees] of the SPA below which the sun is not refracted
+func refractionCutOff(c RefractionConditions) float64 {
+	return -1 * (SunRadius + c.AtmosRefract)
+}
+
+// iterateRefraction solves e = e0 + refraction(e) for the apparent elevation e and returns the refraction [degrees]
+func iterateRefraction(e0 float64, apparentRefraction func(e float64) float64) float64 {
+	e := e0 + apparentRefraction(e0)
+	for i := 0; i < 50; i++ {
+		next := e0 + apparentRefraction(e)
+		if math.Abs(next-e) < 1e-10 {
+			e = next
+			break
+		}
+		e = next
+	}
+	return e - e0
+}
+
+// iterateInverseRefraction solves e0 = e - refraction(e0) for the true elevation e0 and returns the correction [degrees]
+func iterateInverseRefraction(e float64, refraction func(e0 float64) float64) float64 {
+	e0 := e - refraction(e)
+	for i := 0; i < 50; i++ {
+		next := e - refraction(e0)
+		if math.Abs(next-e0) < 1e-10 {
+			e0 = next
+			break
+		}
+		e0 = next
+	}
+	return e - e0
+}
+
+// refractivity coefficient of dry air for visible light (550 nm) [K/millibars]
+const refractivity = 79.53e-6
+
+// standard atmosphere constants
+const (
+	lapseRate           = 0.0065    // temperature lapse rate of the troposphere [K/m]
+	tropopauseTemp      = 216.65    // temperature of the tropopause [K]
+	hydrostaticConstant = 0.0341632 // g*M/R of dry air [K/m]
+	atmosphereTop       = 80000.0   // height above which the refraction is neglected [meters]
+)
+
+// atmosphereLayer holds the temperature [K] and pressure [millibars] of the standard atmosphere at a height,
+// starting at the observer with the local values
+type atmosphereLayer struct {
+	height      float64 // observer elevation [meters]
+	temperature float64 // observer temperature [K]
+	pressure    float64 // observer pressure [millibars]
+	tropopause  float64 // tropopause height [meters]
+	tropoTemp   float64 // tropopause temperature [K]
+	tropoPress  float64 // tropopause pressure [millibars]
+}
+
+func newAtmosphereLayer(c RefractionConditions) atmosphereLayer {
+	a := atmosphereLayer{height: c.Elevation, temperature: c.Temperature + 273.15, pressure: c.Pressure}
+	a.tropoTemp = math.Min(a.temperature, tropopauseTemp)
+	a.tropopause = a.height + (a.temperature-a.tropoTemp)/lapseRate
+	a.tropoPress = a.pressure * math.Pow(a.tropoTemp/a.temperature, hydrostaticConstant/lapseRate)
+	return a
+}
+
+// refractiveIndex calculates the refractive index n - 1 and its derivative with the height [1/m]
+func (a atmosphereLayer) refractiveIndex(height float64) (float64, float64) {
+	var temperature, pressure, gradient float64
+	if height < a.tropopause {
+		temperature = a.temperature - lapseRate*(height-a.height)
+		pressure = a.pressure * math.Pow(temperature/a.temperature, hydrostaticConstant/lapseRate)
+		gradient = -lapseRate
+	} else {
+		temperature = a.tropoTemp
+		pressure = a.tropoPress * math.Exp(-hydrostaticConstant*(height-a.tropopause)/a.tropoTemp)
+	}
+	n1 := refractivity * pressure / temperature
+	return n1, -n1 * (hydrostaticConstant + gradient) / temperature
+}
+
+// grazingElevation calculates the apparent elevation [degrees] of the ray which touches the ground below
+// the observer height, the horizontal for an observer on the ground
+func (m RayTraceRefraction) grazingElevation(c RefractionConditions) float64 {
+	var s spa
+	if c.Height <= 0 || c.Pressure <= 0 {
+		return 0
+	}
+	groundElevation := c.Elevation - c.Height
+	a := newAtmosphereLayer(c)
+	n0, _ := a.refractiveIndex(c.Elevation)
+	ground, _ := a.refractiveIndex(groundElevation)
+	sinZ := (1 + ground) * (EarthMeanRadius + groundElevation) / ((1 + n0) * (EarthMeanRadius + c.Elevation))
+	return -s.rad2deg(math.Acos(math.Min(sinZ, 1)))
+}
+
+// apparentRefraction calculates the refraction [degrees] for the apparent elevation e [degrees]
+func (m RayTraceRefraction) apparentRefraction(e float64, c RefractionConditions) float64 {
+	var s spa
+	if c.Pressure <= 0 || e < m.grazingElevation(c) {
+		return 0
+	}
+	steps := m.Steps
+	if steps <= 0 {
+		steps = 128
+	}
+	a := newAtmosphereLayer(c)
+	r0 := EarthMeanRadius + c.Elevation
+	n1, _ := a.refractiveIndex(c.Elevation)
+	z0 := s.deg2rad(90 - e)
+	k := (1 + n1) * r0 * math.Sin(z0)
+
+	// radius of the ray at the zenith angle z from the invariant n*r*sin(z) = k
+	r := r0
+	radius := func(z float64) float64 {
+		for i := 0; i < 20; i++ {
+			n1, dn := a.refractiveIndex(r - EarthMeanRadius)
+			f := (1+n1)*r*math.Sin(z) - k
+			r -= f / ((1 + n1 + r*dn) * math.Sin(z))
+			if math.Abs(f) < 1e-6 {
+				break
+			}
+		}
+		return r
+	}
+	integrand := func(z float64) float64 {
+		rz := radius(z)
+		n1, dn := a.refractiveIndex(rz - EarthMeanRadius)
+		return -rz * dn / (1 + n1 + rz*dn)
+	}
+	// Simpson integration from the zenith angle high down to low
+	integrate := func(high float64, low float64) float64 {
+		if high <= low {
+			return 0
+		}
+		h := (high - low) / float64(steps*2)
+		sum := integrand(high) + integrand(low)
+		for i := 1; i < steps*2; i++ {
+			weight := 2.0
+			if i%2 == 1 {
+				weight = 4.0
+			}
+			sum += weight * integrand(high-float64(i)*h)
+		}
+		return sum * h / 3
+	}
+
+	top := EarthMeanRadius + math.Max(atmosphereTop, c.Elevation)
+	zTop := math.Asin(math.Min(k/top, 1))
+	if z0 <= math.Pi/2 {
+		r = r0
+		return s.rad2deg(integrate(z0, zTop))
+	}
+	// below the horizontal the ray passes a tangent point, where the zenith angle is 90 degrees,
+	// and rises symmetrically back to the observer elevation
+	r = r0
+	zMirror := math.Pi - z0
+	below := integrate(math.Pi/2, zMirror)
+	r = r0
+	above := integrate(zMirror, zTop)
+	return s.rad2deg(2*below + above)
+}
@@ -0,0 +1,146 @@
+package spa
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+var testRefractionModels = []RefractionModel{
+	SpaRefraction{},
+	SaemundssonRefraction{},
+	BennettRefraction{},
+	NoRefraction{},
+	RayTraceRefraction{},
+}
+
+func TestRefractionRoundTrip(t *testing.T) {
+	for _, site := range [][2]float64{{0, 0}, {1830.14, 0}, {1830.14, 100}, {10000, 10000}} {
+		c := RefractionConditions{Pressure: 820, Temperature: 11, Elevation: site[0], Height: site[1], AtmosRefract: 0.5667}
+		for _, model := range testRefractionModels {
+			for e0 := -2.0; e0 <= 90; e0 += 0.25 {
+				e := ApparentElevation(model, e0, c)
+				if got := TrueElevation(model, e, c); math.Abs(got-e0) > 1e-8 {
+					t.Errorf("%T at %v m, %v m above the ground: true elevation %v, apparent %v, round trip %v",
+						model, site[0], site[1], e0, e, got)
+				}
+			}
+		}
+	}
+}
+
+func TestRefractionBelowFormulaRange(t *testing.T) {
+	c := RefractionConditions{Pressure: 1010, Temperature: 10, AtmosRefract: 0.5667}
+	limbs := map[string]func(float64) float64{
+		"SaemundssonRefraction": func(e0 float64) float64 { return SaemundssonRefraction{}.limbRefraction(e0, c) },
+		"BennettRefraction":     func(e0 float64) float64 { return BennettRefraction{}.limbRefraction(e0, c) },
+	}
+	for name, limbRefraction := range limbs {
+		previous := limbRefraction(-1)
+		for e0 := -1.1; e0 >= -8; e0 -= 0.1 {
+			refraction := limbRefraction(e0)
+			// the limb refraction grows towards the lower elevations and is held at its maximum
+			if refraction < previous-1e-12 {
+				t.Errorf("%v at %v: refraction %v decreases from %v", name, e0, refraction, previous)
+			}
+			if math.Abs(refraction-previous) > 0.05 {
+				t.Errorf("%v at %v: refraction jumps from %v to %v", name, e0, previous, refraction)
+			}
+			previous = refraction
+		}
+	}
+
+	// like the SPA formula, the sun below -(SunRadius + AtmosRefract) is not refracted
+	for _, model := range []RefractionModel{SpaRefraction{}, SaemundssonRefraction{}, BennettRefraction{}} {
+		for _, e0 := range []float64{-0.84, -2, -30, -89} {
+			if refraction := model.Refraction(e0, c); refraction != 0 {
+				t.Errorf("%T at %v: got %v, want 0", model, e0, refraction)
+			}
+		}
+	}
+
+	// rays below the horizontal of an observer on the ground hit the earth
+	if refraction := (RayTraceRefraction{}).Refraction(-3, c); refraction != 0 {
+		t.Errorf("RayTraceRefraction on the ground at -3: got %v, want 0", refraction)
+	}
+}
+
+// a sun deep below the horizon gives the same night-time output with every refraction model
+func TestRefractionSunBelowHorizon(t *testing.T) {
+	night := time.Date(2003, 10, 17, 0, 30, 0, 0, time.FixedZone("", -7*3600))
+	want := newTestSpa(t)
+	want.SetDate(night)
+	if err := want.Calculate(); err != nil {
+		t.Fatal(err)
+	}
+	if want.GetE0() > -30 {
+		t.Fatalf("sun at %v, want deep below the horizon", want.GetE0())
+	}
+	for _, model := range testRefractionModels {
+		s := newTestSpa(t)
+		s.SetDate(night)
+		s.SetRefractionModel(model)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		if s.GetDelE() != 0 || s.GetZenith() != want.GetZenith() || s.GetIncidence() != want.GetIncidence() {
+			t.Errorf("%T: got refraction %v, zenith %v and incidence %v, want 0, %v and %v",
+				model, s.GetDelE(), s.GetZenith(), s.GetIncidence(), want.GetZenith(), want.GetIncidence())
+		}
+	}
+}
+
+// a high inland site on the ground sees the horizontal as the grazing ray, not the sea level horizon
+func TestRayTraceInlandSite(t *testing.T) {
+	var m RayTraceRefraction
+	c := RefractionConditions{Pressure: 820, Temperature: 11, Elevation: 1830.14, AtmosRefract: 0.5667}
+	if grazing := m.grazingElevation(c); grazing != 0 {
+		t.Errorf("grazing elevation on the ground: got %v, want 0", grazing)
+	}
+	if refraction := m.Refraction(-1, c); refraction != 0 {
+		t.Errorf("refraction on the ground at -1: got %v, want 0", refraction)
+	}
+	// the horizontal ray agrees with the formula of Bennett
+	if horizon, bennett := m.InverseRefraction(0, c), (BennettRefraction{}).InverseRefraction(0, c); math.Abs(horizon-bennett) > 0.05 {
+		t.Errorf("refraction at the horizon: got %v, want about %v", horizon, bennett)
+	}
+
+	// the observer height of the instance, not the site elevation, lifts the observer above the ground
+	s := newTestSpa(t)
+	s.SetRefractionModel(m)
+	if refraction := s.(*spa).refraction(-1); refraction != 0 {
+		t.Errorf("instance refraction at -1: got %v, want 0", refraction)
+	}
+
+	// the same site 100 m above the valley floor
+	c.Height = 100
+	if grazing := m.grazingElevation(c); grazing > -0.2 || grazing < -0.4 {
+		t.Errorf("grazing elevation 100 m above the ground: got %v, want about -0.3", grazing)
+	}
+}
+
+func TestGetTrueElevation(t *testing.T) {
+	for _, mode := range []ObserverModes{ObserverGround, ObserverSpace} {
+		for _, model := range append([]RefractionModel{nil}, testRefractionModels...) {
+			t.Run(fmt.Sprintf("%v/%T", mode, model), func(t *testing.T) {
+				s, err := NewSpa(time.Date(2003, 10, 17, 12, 30, 30, 0, time.FixedZone("", -7*3600)),
+					39.742476, -105.1786, 1830.14, 820, 11, 67, 0, 30, -10, 0.5667)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.SetObserverMode(mode)
+				s.SetRefractionModel(model)
+				for e0 := -2.0; e0 <= 90; e0 += 0.25 {
+					refraction := s.(*spa).refraction(e0)
+					if mode == ObserverSpace && refraction != 0 {
+						t.Fatalf("refraction in space at %v: %v", e0, refraction)
+					}
+					if got := s.GetTrueElevation(e0 + refraction); math.Abs(got-e0) > 1e-8 {
+						t.Errorf("true elevation %v, apparent %v, round trip %v", e0, e0+refraction, got)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package spa
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Seasons holds the equinox and solstice instants of a year
+type Seasons struct {
+	MarchEquinox     time.Time // apparent sun longitude 0 degrees
+	JuneSolstice     time.Time // apparent sun longitude 90 degrees
+	SeptemberEquinox time.Time // apparent sun longitude 180 degrees
+	DecemberSolstice time.Time // apparent sun longitude 270 degrees
+}
+
+// FindSeasons calculates the equinox and solstice instants (UTC) of the year, valid range: -2000 to 6000.
+// The difference between earth rotation time and terrestrial time (deltaT) is given in seconds.
+// Like the SPA input, dates before 15 October 1582 are in the Julian calendar.
+func FindSeasons(year int, deltaT float64) (Seasons, error) {
+	var seasons Seasons
+	var err error
+	if seasons.MarchEquinox, err = FindSunLongitude(year, 0, deltaT); err != nil {
+		return seasons, err
+	}
+	if seasons.JuneSolstice, err = FindSunLongitude(year, 90, deltaT); err != nil {
+		return seasons, err
+	}
+	if seasons.SeptemberEquinox, err = FindSunLongitude(year, 180, deltaT); err != nil {
+		return seasons, err
+	}
+	if seasons.DecemberSolstice, err = FindSunLongitude(year, 270, deltaT); err != nil {
+		return seasons, err
+	}
+	return seasons, nil
+}
+
+// FindSunLongitude calculates the instant (UTC) of the year when the apparent sun longitude reaches lamda [degrees],
+// counted from the March equinox. The difference between earth rotation time and terrestrial time (deltaT) is given in seconds.
+func FindSunLongitude(year int, lamda float64, deltaT float64) (time.Time, error) {
+	sun, err := newSeasonsSpa(year, deltaT)
+	if err != nil {
+		return time.Time{}, err
+	}
+	lamda = sun.limitDegrees(lamda)
+
+	// start at the March equinox and step along the mean motion of the sun
+	start := sun.julianDay(year, 3, 20, 0, 0, 0, 0, 0) + lamda*365.2422/360.0
+	for attempt := 0; attempt < 3; attempt++ {
+		sun.jd = start
+		converged := false
+		for i := 0; i < 50 && !converged; i++ {
+			sun.calculateGeocentricSunRightAscensionAndDeclination()
+			correction := 58.0 * math.Sin(sun.deg2rad(lamda-sun.lamda))
+			sun.jd += correction
+			converged = math.Abs(correction) < 1e-7
+		}
+		if !converged {
+			break
+		}
+		// the Julian calendar drifts against the seasons, keep the instant in the requested year
+		dt := sun.julianDayToDate(sun.jd)
+		switch {
+		case dt.Year() < year:
+			start = sun.jd + 365.2422
+		case dt.Year() > year:
+			start = sun.jd - 365.2422
+		default:
+			return dt, nil
+		}
+	}
+	return time.Time{}, errors.New("sun longitude not found")
+}
+
+// FindPerihelion calculates the instant (UTC) of the year with the smallest earth radius vector and
+// the radius vector [Astronomical Units, AU]. The difference between earth rotation time and
+// terrestrial time (deltaT) is given in seconds. The radius vector changes slowly near the apsis,
+// so the truncated earth periodic terms can shift the instant by some ten minutes.
+func FindPerihelion(year int, deltaT float64) (time.Time, float64, error) {
+	return findApsis(year, deltaT, false)
+}
+
+// FindAphelion calculates the instant (UTC) of the year with the largest earth radius vector and
+// the radius vector [Astronomical Units, AU]. The difference between earth rotation time and
+// terrestrial time (deltaT) is given in seconds. See FindPerihelion for the accuracy.
+func FindAphelion(year int, deltaT float64) (time.Time, float64, error) {
+	return findApsis(year, deltaT, true)
+}
+
+func newSeasonsSpa(year int, deltaT float64) (spa, error) {
+	var sun spa
+	sun.init()
+	sun.deltaT = deltaT
+	if (year < -2000) || (year > 6000) {
+		return sun, errors.New("invalid year")
+	}
+	if math.Abs(deltaT) > 8000 {
+		return sun, errors.New("invalid difference between earth rotation time and terrestrial time (deltaT)")
+	}
+	return sun, nil
+}
+
+func findApsis(year int, deltaT float64, aphelion bool) (time.Time, float64, error) {
+	sun, err := newSeasonsSpa(year, deltaT)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	// signed radius vector, the apsis is always a minimum
+	radius := func(jde float64) float64 {
+		r := sun.earthRadiusVector(sun.julianEphemerisMillennium(sun.julianEphemerisCentury(jde)))
+		if aphelion {
+			return -r
+		}
+		return r
+	}
+
+	// mean apsis from the anomalistic year (Meeus, Astronomical Algorithms, chapter 38)
+	k := math.Round((float64(year) - 2000.01) * 0.99997)
+	if aphelion {
+		k += 0.5
+	}
+	for attempt := 0; attempt < 3; attempt++ {
+		jde := 2451547.507 + 365.2596358*k + 0.0000000156*k*k
+
+		// the moon shifts the apsis by up to a few days, scan for the minimum
+		const step = 0.05
+		best := jde
+		for t := jde - 5; t <= jde+5; t += step {
+			if radius(t) < radius(best) {
+				best = t
+			}
+		}
+		// golden section search around the minimum
+		low, high := best-step, best+step
+		g := (math.Sqrt(5) - 1) / 2
+		for high-low > 1e-6 {
+			a := high - g*(high-low)
+			b := low + g*(high-low)
+			if radius(a) < radius(b) {
+				high = b
+			} else {
+				low = a
+			}
+		}
+		jde = (low + high) / 2
+
+		dt := sun.julianDayToDate(jde - deltaT/86400.0)
+		switch {
+		case dt.Year() < year:
+			k++
+		case dt.Year() > year:
+			k--
+		default:
+			return dt, math.Abs(radius(jde)), nil
+		}
+	}
+	return time.Time{}, 0, errors.New("apsis not found")
+}
+
+// julianDayToDate converts the julian day to a UTC date, the Julian calendar is used before 15 October 1582
+// (Meeus, Astronomical Algorithms, chapter 7)
+func (s *spa) julianDayToDate(jd float64) time.Time {
+	z := math.Floor(jd + 0.5)
+	f := jd + 0.5 - z
+	a := z
+	if z >= 2299161 {
+		alpha := math.Floor((z - 1867216.25) / 36524.25)
+		a = z + 1 + alpha - math.Floor(alpha/4)
+	}
+	b := a + 1524
+	c := math.Floor((b - 122.1) / 365.25)
+	d := math.Floor(365.25 * c)
+	e := math.Floor((b - d) / 30.6001)
+
+	day := int(b - d - math.Floor(30.6001*e))
+	month := int(e - 13)
+	if e < 14 {
+		month = int(e - 1)
+	}
+	year := int(c - 4715)
+	if month > 2 {
+		year = int(c - 4716)
+	}
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Add(time.Duration(math.Round(f*86400)) * time.Second)
+}
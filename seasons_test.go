@@ -0,0 +1,68 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// 2024 equinox and solstice instants of the US Naval Observatory
+func TestFindSeasons(t *testing.T) {
+	seasons, err := FindSeasons(2024, 69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		got  time.Time
+		want time.Time
+	}{
+		{"March equinox", seasons.MarchEquinox, time.Date(2024, 3, 20, 3, 6, 0, 0, time.UTC)},
+		{"June solstice", seasons.JuneSolstice, time.Date(2024, 6, 20, 20, 51, 0, 0, time.UTC)},
+		{"September equinox", seasons.SeptemberEquinox, time.Date(2024, 9, 22, 12, 44, 0, 0, time.UTC)},
+		{"December solstice", seasons.DecemberSolstice, time.Date(2024, 12, 21, 9, 21, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		if d := test.got.Sub(test.want).Seconds(); math.Abs(d) > 60 {
+			t.Errorf("%v: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+}
+
+// 2024 perihelion and aphelion of the US Naval Observatory
+func TestFindApsis(t *testing.T) {
+	perihelion, radius, err := FindPerihelion(2024, 69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 3, 0, 39, 0, 0, time.UTC); math.Abs(perihelion.Sub(want).Minutes()) > 30 {
+		t.Errorf("perihelion: got %v, want %v", perihelion, want)
+	}
+	if math.Abs(radius-0.983307) > 1e-5 {
+		t.Errorf("perihelion radius: got %v, want 0.983307", radius)
+	}
+	aphelion, radius, err := FindAphelion(2024, 69)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 7, 5, 5, 6, 0, 0, time.UTC); math.Abs(aphelion.Sub(want).Minutes()) > 30 {
+		t.Errorf("aphelion: got %v, want %v", aphelion, want)
+	}
+	if math.Abs(radius-1.016725) > 1e-5 {
+		t.Errorf("aphelion radius: got %v, want 1.016725", radius)
+	}
+}
+
+// the sun longitude search keeps the instant in the requested year of the Julian calendar
+func TestFindSunLongitudeJulianCalendar(t *testing.T) {
+	equinox, err := FindSunLongitude(-1000, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if equinox.Year() != -1000 {
+		t.Errorf("got %v, want year -1000", equinox)
+	}
+	if _, err := FindSeasons(7000, 0); err == nil {
+		t.Errorf("year 7000: no error")
+	}
+}
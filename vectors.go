@@ -0,0 +1,65 @@
+package spa
+
+import "math"
+
+// AstronomicalUnit is the length of the astronomical unit [km]
+const AstronomicalUnit = 149597870.7
+
+// SunVectors holds the sun direction as cartesian unit vectors and the sun distance
+type SunVectors struct {
+	ENU         [3]float64 // topocentric East-North-Up unit vector (uncorrected)
+	ENUApparent [3]float64 // topocentric East-North-Up unit vector (refraction corrected)
+	ECEF        [3]float64 // geocentric Earth-centred Earth-fixed unit vector (without polar motion)
+	ECI         [3]float64 // geocentric inertial unit vector of the J2000 mean equator and equinox
+	DistanceAU  float64    // geocentric sun distance [Astronomical Units, AU]
+	DistanceKm  float64    // geocentric sun distance [km]
+	RangeKm     float64    // topocentric sun distance [km]
+}
+
+// sunVectors calculates the sun vectors from the apparent geocentric right ascension and declination of the date,
+// the Greenwich sidereal time and the observer position. The directions include the annual aberration, as seen
+// from the earth.
+func (s *spa) sunVectors() SunVectors {
+	var v SunVectors
+	v.DistanceAU = s.r
+	v.DistanceKm = s.r * AstronomicalUnit
+
+	// true equator and equinox of the date
+	trueOfDate := unitVector(s.deg2rad(s.alpha), s.deg2rad(s.delta))
+
+	v.ECEF = rotationZ(s.deg2rad(s.nu)).apply(trueOfDate)
+
+	v.ECI = s.frameMatrix(FrameJ2000).transpose().apply(trueOfDate)
+
+	// observer in the earth-fixed frame [km]
+	latRad := s.deg2rad(s.latitude)
+	lonRad := s.deg2rad(s.longitude)
+	x, y, z := GeodeticToECEF(s.ellipsoid, s.latitude, s.longitude, s.elevation)
+	observer := [3]float64{x / 1000.0, y / 1000.0, z / 1000.0}
+
+	var topocentric [3]float64
+	for i := range topocentric {
+		topocentric[i] = v.ECEF[i]*v.DistanceKm - observer[i]
+	}
+	v.RangeKm = math.Sqrt(topocentric[0]*topocentric[0] + topocentric[1]*topocentric[1] + topocentric[2]*topocentric[2])
+
+	// earth-fixed to East-North-Up at the geodetic latitude and longitude
+	toENU := matrix3{
+		{-math.Sin(lonRad), math.Cos(lonRad), 0},
+		{-math.Sin(latRad) * math.Cos(lonRad), -math.Sin(latRad) * math.Sin(lonRad), math.Cos(latRad)},
+		{math.Cos(latRad) * math.Cos(lonRad), math.Cos(latRad) * math.Sin(lonRad), math.Sin(latRad)},
+	}
+	v.ENU = toENU.apply(topocentric)
+	for i := range v.ENU {
+		v.ENU[i] /= v.RangeKm
+	}
+
+	azimuthRad := s.deg2rad(s.azimuth)
+	elevationRad := s.deg2rad(s.e)
+	v.ENUApparent = [3]float64{
+		math.Cos(elevationRad) * math.Sin(azimuthRad),
+		math.Cos(elevationRad) * math.Cos(azimuthRad),
+		math.Sin(elevationRad),
+	}
+	return v
+}
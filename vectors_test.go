@@ -0,0 +1,47 @@
+package spa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// the East-North-Up vectors point along the topocentric azimuth and elevation of the instance
+func TestSunVectors(t *testing.T) {
+	for _, date := range []time.Time{
+		time.Date(2003, 10, 17, 12, 30, 30, 0, time.FixedZone("", -7*3600)),
+		time.Date(2003, 10, 17, 7, 0, 0, 0, time.FixedZone("", -7*3600)),
+		time.Date(2024, 6, 20, 18, 0, 0, 0, time.FixedZone("", -7*3600)),
+	} {
+		s := newTestSpa(t)
+		s.SetDate(date)
+		if err := s.Calculate(); err != nil {
+			t.Fatal(err)
+		}
+		v := s.GetSunVectors()
+		for _, test := range []struct {
+			name      string
+			enu       [3]float64
+			azimuth   float64
+			elevation float64
+		}{
+			{"ENU", v.ENU, s.GetAzimuth(), 90 - s.GetZenith0()},
+			{"ENUApparent", v.ENUApparent, s.GetAzimuth(), 90 - s.GetZenith()},
+		} {
+			norm := math.Sqrt(test.enu[0]*test.enu[0] + test.enu[1]*test.enu[1] + test.enu[2]*test.enu[2])
+			azimuth := 180 / math.Pi * math.Atan2(test.enu[0], test.enu[1])
+			elevation := 180 / math.Pi * math.Asin(test.enu[2])
+			if math.Abs(norm-1) > 1e-12 {
+				t.Errorf("%v %v: got norm %v", date, test.name, norm)
+			}
+			// one arc second
+			if math.Abs(s.(*spa).limitDegrees180pm(azimuth-test.azimuth)) > 1.0/3600 || math.Abs(elevation-test.elevation) > 1.0/3600 {
+				t.Errorf("%v %v: got azimuth %v and elevation %v, want %v and %v", date, test.name,
+					azimuth, elevation, test.azimuth, test.elevation)
+			}
+		}
+		if math.Abs(v.DistanceKm-s.GetR()*AstronomicalUnit) > 1e-6 || math.Abs(v.RangeKm-v.DistanceKm) > 6400 {
+			t.Errorf("%v: got distance %v and range %v", date, v.DistanceKm, v.RangeKm)
+		}
+	}
+}